@@ -20,8 +20,11 @@ import (
 	"fmt"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/builder"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/cache"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/clusterconfig"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/filter"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/format"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/trust"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/gemfire"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/geode"
 	"os"
@@ -29,17 +32,24 @@ import (
 )
 
 func main() {
-	processRequest := common.Exchange
+	knownLocators, err := trust.FromEnvironment()
+	checkError(err)
+	processRequest := common.NewExchange(knownLocators)
 	formatter, err := format.New(filter.GOJQFilter)
 	checkError(err)
-	commonCode, err := common.NewCommandProcessor(processRequest, formatter, builder.BuildRequest)
+	specCache, err := cache.FromEnvironment()
+	checkError(err)
+	commonCode, err := common.NewCommandProcessor(processRequest, formatter, builder.BuildRequest, specCache)
+	checkError(err)
+	// apply, plan and export-config work on the configuration of the cluster as a whole
+	commonCode, err = clusterconfig.NewCommandProcessor(commonCode)
 	checkError(err)
 
 	// figure out who is calling. If invoked as a standalone cli
 	if (strings.HasSuffix(os.Args[0], "main_go") ||
 		strings.HasSuffix(os.Args[0], "gemfire")) &&
 		!strings.Contains(os.Args[0], "cf/plugins") {
-		geodeCommand, err := geode.New(commonCode)
+		geodeCommand, err := geode.New(commonCode, processRequest)
 		checkError(err)
 		err = geodeCommand.Run(os.Args)
 		checkError(err)
@@ -54,6 +64,6 @@ func main() {
 func checkError(err error) {
 	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(1)
+		os.Exit(common.ExitCode(err))
 	}
 }
@@ -0,0 +1,106 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package domainfakes
+
+import (
+	"sync"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+)
+
+type FakeTokenRenewer struct {
+	RenewTokenStub        func() (string, error)
+	renewTokenMutex       sync.RWMutex
+	renewTokenArgsForCall []struct {
+	}
+	renewTokenReturns struct {
+		result1 string
+		result2 error
+	}
+	renewTokenReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTokenRenewer) RenewToken() (string, error) {
+	fake.renewTokenMutex.Lock()
+	ret, specificReturn := fake.renewTokenReturnsOnCall[len(fake.renewTokenArgsForCall)]
+	fake.renewTokenArgsForCall = append(fake.renewTokenArgsForCall, struct {
+	}{})
+	fake.recordInvocation("RenewToken", []interface{}{})
+	fake.renewTokenMutex.Unlock()
+	if fake.RenewTokenStub != nil {
+		return fake.RenewTokenStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.renewTokenReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTokenRenewer) RenewTokenCallCount() int {
+	fake.renewTokenMutex.RLock()
+	defer fake.renewTokenMutex.RUnlock()
+	return len(fake.renewTokenArgsForCall)
+}
+
+func (fake *FakeTokenRenewer) RenewTokenCalls(stub func() (string, error)) {
+	fake.renewTokenMutex.Lock()
+	defer fake.renewTokenMutex.Unlock()
+	fake.RenewTokenStub = stub
+}
+
+func (fake *FakeTokenRenewer) RenewTokenReturns(result1 string, result2 error) {
+	fake.renewTokenMutex.Lock()
+	defer fake.renewTokenMutex.Unlock()
+	fake.RenewTokenStub = nil
+	fake.renewTokenReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTokenRenewer) RenewTokenReturnsOnCall(i int, result1 string, result2 error) {
+	fake.renewTokenMutex.Lock()
+	defer fake.renewTokenMutex.Unlock()
+	fake.RenewTokenStub = nil
+	if fake.renewTokenReturnsOnCall == nil {
+		fake.renewTokenReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.renewTokenReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTokenRenewer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.renewTokenMutex.RLock()
+	defer fake.renewTokenMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeTokenRenewer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ domain.TokenRenewer = new(FakeTokenRenewer)
@@ -15,7 +15,11 @@
 
 package domain
 
-import "code.cloudfoundry.org/cli/plugin"
+import (
+	"time"
+
+	"code.cloudfoundry.org/cli/plugin"
+)
 
 var VersionType = plugin.VersionType{Major: 1, Minor: 0, Build: 7}
 
@@ -35,12 +39,36 @@ type ConnectionData struct {
 	Token          string
 	UseToken       bool
 	LocatorAddress string
+	// AuthMode selects "basic" or "token" authentication, by default token when UseToken is set
+	// because the locator requires it
+	AuthMode string
+	// CACert is the path of a PEM file with certificates trusted in addition to the system ones
+	CACert string
+	// SkipSSLValidation disables the verification of the locator's certificate
+	SkipSSLValidation bool
+	// ClientCert is the path of the client certificate presented to the locator, either a PEM
+	// file or a PKCS#12 bundle which also holds the key
+	ClientCert string
+	// ClientKey is the path of the PEM private key of a PEM client certificate
+	ClientKey string
+	// ClientCertPassphrase decrypts a PKCS#12 bundle
+	ClientCertPassphrase string
+	// TokenRenewer re-acquires the token when the locator rejects it, nil when it cannot be renewed
+	TokenRenewer TokenRenewer
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . TokenRenewer
+
+// TokenRenewer provides a new bearer token for a connection whose token has expired
+type TokenRenewer interface {
+	RenewToken() (token string, err error)
 }
 
 // ServiceKeyUsers holds the username and password for users identified in a CF service key
 type ServiceKeyUsers struct {
-	Password string `json:"password"`
-	Username string `json:"username"`
+	Password string   `json:"password"`
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
 }
 
 // ServiceKeyUrls holds URL information for endpoints to the PCC manageability service
@@ -71,6 +99,18 @@ type RestEndPoint struct {
 	Parameters  []RestAPIParam
 }
 
+// SpecCacheEntry holds the endpoints discovered for a locator together with the
+// details needed to revalidate them against the API documentation they came from
+type SpecCacheEntry struct {
+	LocatorAddress     string                  `json:"locatorAddress"`
+	SpecURL            string                  `json:"specUrl"`
+	ETag               string                  `json:"etag,omitempty"`
+	LastModified       string                  `json:"lastModified,omitempty"`
+	FetchedAt          time.Time               `json:"fetchedAt"`
+	UseToken           bool                    `json:"useToken"`
+	AvailableEndpoints map[string]RestEndPoint `json:"availableEndpoints"`
+}
+
 // RestAPI is used to parse the swagger json response
 // first key: url | second key: method (get/post) | value: RestAPIDetail
 type RestAPI struct {
@@ -9,6 +9,8 @@ require (
 	github.com/onsi/ginkgo v1.15.2
 	github.com/onsi/gomega v1.10.1
 	github.com/vito/go-interact v0.0.0-20171111012221-fa338ed9e9ec
+	golang.org/x/crypto v0.14.0
+	gopkg.in/yaml.v2 v2.3.0
 )
 
 require (
@@ -40,7 +42,6 @@ require (
 	github.com/sirupsen/logrus v1.4.2 // indirect
 	github.com/tebeka/strftime v0.1.3 // indirect
 	github.com/tedsuo/rata v1.0.0 // indirect
-	golang.org/x/crypto v0.14.0 // indirect
 	golang.org/x/mod v0.8.0 // indirect
 	golang.org/x/net v0.17.0 // indirect
 	golang.org/x/sys v0.13.0 // indirect
@@ -50,5 +51,4 @@ require (
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 	gopkg.in/cheggaaa/pb.v1 v1.0.28 // indirect
 	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
-	gopkg.in/yaml.v2 v2.3.0 // indirect
 )
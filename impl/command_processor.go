@@ -22,4 +22,6 @@ import "github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
 // CommandProcessor interface provides a way to kick of main processing cycle
 type CommandProcessor interface {
 	ProcessCommand(commandData *domain.CommandData) error
+	LoadEndPoints(commandData *domain.CommandData) error
+	ExecuteCommand(commandData *domain.CommandData) (string, error)
 }
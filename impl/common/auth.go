@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"code.cloudfoundry.org/cli/cf/errors"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+)
+
+// Authentication modes selected with the '--auth' option
+const (
+	AuthBasic = "basic"
+	AuthToken = "token"
+)
+
+// AuthModes lists the values accepted by the '--auth' option
+var AuthModes = []string{AuthBasic, AuthToken}
+
+// ApplyAuthOptions sets the authentication of the connection from the '--auth', '--token' and
+// '--token-file' options, or the 'GEODE_TOKEN' environment variable. A token that is already
+// known, e.g. the access token of the cf CLI, is only replaced by one given as an option
+func ApplyAuthOptions(commandData *domain.CommandData) error {
+	parameters := commandData.UserCommand.Parameters
+	connectionData := &commandData.ConnnectionData
+
+	connectionData.AuthMode = GetOption(parameters, []string{"--auth"})
+	if connectionData.AuthMode != "" && !Contains(AuthModes, connectionData.AuthMode) {
+		return errors.New("Invalid auth: " + connectionData.AuthMode + ", use one of: " + strings.Join(AuthModes, ", "))
+	}
+
+	if token := GetOption(parameters, []string{"--token"}); token != "" {
+		connectionData.Token = token
+	} else if tokenFile := GetOption(parameters, []string{"--token-file"}); tokenFile != "" {
+		content, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return errors.New("Unable to read the token file: " + err.Error())
+		}
+		connectionData.Token = strings.TrimSpace(string(content))
+	} else if connectionData.Token == "" {
+		connectionData.Token = os.Getenv("GEODE_TOKEN")
+	}
+	return nil
+}
+
+// UsesToken reports whether requests authenticate with a bearer token rather than a username and
+// password. Unless selected with the '--auth' option, a token is used when the locator requires one
+func UsesToken(connectionData domain.ConnectionData) bool {
+	if connectionData.AuthMode != "" {
+		return connectionData.AuthMode == AuthToken
+	}
+	return connectionData.UseToken
+}
+
+// BearerToken provides the value of the Authorization header for the token of the connection,
+// reporting an error when there is no token
+func BearerToken(connectionData domain.ConnectionData) (string, error) {
+	token := strings.TrimSpace(connectionData.Token)
+	// the cf CLI provides its access token with the scheme
+	if strings.HasPrefix(strings.ToLower(token), "bearer ") {
+		token = strings.TrimSpace(token[len("bearer "):])
+	}
+	if token == "" {
+		return "", NewAuthError("The locator requires a bearer token. " +
+			"Provide one with --token, --token-file or the GEODE_TOKEN environment variable")
+	}
+	return "Bearer " + token, nil
+}
@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Auth", func() {
+
+	var commandData domain.CommandData
+
+	BeforeEach(func() {
+		commandData = domain.CommandData{UserCommand: domain.UserCommand{Parameters: map[string]string{}}}
+	})
+
+	Context("ApplyAuthOptions", func() {
+
+		AfterEach(func() {
+			os.Unsetenv("GEODE_TOKEN")
+		})
+
+		It("Takes the token from the option before the environment", func() {
+			os.Setenv("GEODE_TOKEN", "from-env")
+			Expect(ApplyAuthOptions(&commandData)).To(Succeed())
+			Expect(commandData.ConnnectionData.Token).To(Equal("from-env"))
+
+			commandData.UserCommand.Parameters["--token"] = "from-option"
+			Expect(ApplyAuthOptions(&commandData)).To(Succeed())
+			Expect(commandData.ConnnectionData.Token).To(Equal("from-option"))
+		})
+
+		It("Reads the token from a file", func() {
+			file, err := ioutil.TempFile("", "token")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(file.Name())
+			_, _ = file.WriteString("from-file\n")
+			file.Close()
+
+			commandData.UserCommand.Parameters["--token-file"] = file.Name()
+			Expect(ApplyAuthOptions(&commandData)).To(Succeed())
+			Expect(commandData.ConnnectionData.Token).To(Equal("from-file"))
+		})
+
+		It("Reports a token file that cannot be read", func() {
+			commandData.UserCommand.Parameters["--token-file"] = "missing-token"
+			err := ApplyAuthOptions(&commandData)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unable to read the token file"))
+		})
+
+		It("Keeps a known token unless one is given as an option", func() {
+			os.Setenv("GEODE_TOKEN", "from-env")
+			commandData.ConnnectionData.Token = "cf-token"
+			Expect(ApplyAuthOptions(&commandData)).To(Succeed())
+			Expect(commandData.ConnnectionData.Token).To(Equal("cf-token"))
+		})
+
+		It("Validates the authentication mode", func() {
+			commandData.UserCommand.Parameters["--auth"] = "kerberos"
+			Expect(ApplyAuthOptions(&commandData)).To(MatchError("Invalid auth: kerberos, use one of: basic, token"))
+		})
+	})
+
+	Context("UsesToken", func() {
+		It("Follows the locator unless the user selected a mode", func() {
+			Expect(UsesToken(domain.ConnectionData{})).To(BeFalse())
+			Expect(UsesToken(domain.ConnectionData{UseToken: true})).To(BeTrue())
+			Expect(UsesToken(domain.ConnectionData{UseToken: true, AuthMode: AuthBasic})).To(BeFalse())
+			Expect(UsesToken(domain.ConnectionData{AuthMode: AuthToken})).To(BeTrue())
+		})
+	})
+
+	Context("BearerToken", func() {
+		It("Adds the scheme once", func() {
+			Expect(BearerToken(domain.ConnectionData{Token: "abc"})).To(Equal("Bearer abc"))
+			Expect(BearerToken(domain.ConnectionData{Token: "bearer abc"})).To(Equal("Bearer abc"))
+		})
+
+		It("Reports a missing token as an authentication failure", func() {
+			_, err := BearerToken(domain.ConnectionData{})
+			Expect(err).To(HaveOccurred())
+			Expect(ExitCode(err)).To(Equal(ExitCodeAuthFailure))
+		})
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -100,10 +101,17 @@ func BuildRequest(restEndPoint domain.RestEndPoint, commandData *domain.CommandD
 	} else {
 		request, err = http.NewRequest(httpAction, requestURL, bodyReader)
 	}
+	if err != nil {
+		return nil, err
+	}
+	request = common.WithConnectionData(request, connectionData)
 
-	if connectionData.UseToken {
-		var bearer = "Bearer " + connectionData.Token
-		request.Header.Add("Authorization", bearer)
+	if common.UsesToken(connectionData) {
+		bearer, err := common.BearerToken(connectionData)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Authorization", bearer)
 	} else {
 		request.SetBasicAuth(connectionData.Username, connectionData.Password)
 	}
@@ -116,12 +124,15 @@ func BuildRequest(restEndPoint domain.RestEndPoint, commandData *domain.CommandD
 	return
 }
 
+// getBodyReader provides the body given as JSON or as @<file>. A file is read into memory so that
+// the request can be replayed, e.g. after renewing a token
 func getBodyReader(jsonFile string) (bodyReader io.Reader, err error) {
 	if jsonFile[0] == '@' && len(jsonFile) > 1 {
-		bodyReader, err = os.Open(jsonFile[1:])
+		content, err := ioutil.ReadFile(jsonFile[1:])
 		if err != nil {
-			return
+			return nil, err
 		}
+		bodyReader = bytes.NewReader(content)
 	} else {
 		bodyReader = strings.NewReader(jsonFile)
 	}
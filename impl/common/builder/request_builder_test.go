@@ -153,5 +153,49 @@ var _ = Describe("RequestBuilder", func() {
 				Expect(request.URL.String()).To(Equal(expectedListURL))
 			})
 		})
+
+		Context("Authentication", func() {
+
+			BeforeEach(func() {
+				commandData.UserCommand.Parameters["--regionConfig"] = "{}"
+				commandData.ConnnectionData.Username = "user"
+				commandData.ConnnectionData.Password = "password"
+			})
+
+			It("Uses basic authentication when the locator does not require a token", func() {
+				commandData.ConnnectionData.Token = "token"
+				request, err := buildRequest(restEndPoint, &commandData)
+				Expect(err).NotTo(HaveOccurred())
+				username, password, ok := request.BasicAuth()
+				Expect(ok).To(BeTrue())
+				Expect(username).To(Equal("user"))
+				Expect(password).To(Equal("password"))
+			})
+
+			It("Uses the bearer token when the locator requires one", func() {
+				commandData.ConnnectionData.UseToken = true
+				commandData.ConnnectionData.Token = "bearer abc"
+				request, err := buildRequest(restEndPoint, &commandData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(request.Header.Values("Authorization")).To(Equal([]string{"Bearer abc"}))
+			})
+
+			It("Uses the authentication selected by the user", func() {
+				commandData.ConnnectionData.AuthMode = "token"
+				commandData.ConnnectionData.Token = "abc"
+				request, err := buildRequest(restEndPoint, &commandData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(request.Header.Get("Authorization")).To(Equal("Bearer abc"))
+			})
+
+			It("Returns an error when the locator requires a token and none was supplied", func() {
+				commandData.ConnnectionData.UseToken = true
+				request, err := buildRequest(restEndPoint, &commandData)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("--token"))
+				Expect(common.ExitCode(err)).To(Equal(common.ExitCodeAuthFailure))
+				Expect(request).To(BeNil())
+			})
+		})
 	})
 })
@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+)
+
+// DefaultTTL is how long discovered endpoints are used before they are revalidated
+const DefaultTTL = time.Hour
+
+type specCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// New provides a constructor for the on-disk implementation of the common.SpecCache interface.
+// An empty dir disables caching
+func New(dir string, ttl time.Duration) (*specCache, error) {
+	if ttl < 0 {
+		return nil, errors.New("spec cache TTL must not be negative")
+	}
+	return &specCache{dir: dir, ttl: ttl}, nil
+}
+
+// FromEnvironment constructs the spec cache using the 'GEODE_SPEC_CACHE_DIR' and
+// 'GEODE_SPEC_CACHE_TTL' environment variables, falling back to the user cache directory
+// and DefaultTTL
+func FromEnvironment() (*specCache, error) {
+	dir := os.Getenv("GEODE_SPEC_CACHE_DIR")
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err == nil {
+			dir = filepath.Join(userCacheDir, "gemfire", "specs")
+		}
+	}
+	ttl := DefaultTTL
+	if value := os.Getenv("GEODE_SPEC_CACHE_TTL"); value != "" {
+		var err error
+		ttl, err = time.ParseDuration(value)
+		if err != nil {
+			return nil, errors.New("invalid GEODE_SPEC_CACHE_TTL: " + err.Error())
+		}
+	}
+	return New(dir, ttl)
+}
+
+// Load reads the cached entry for a locator
+func (sc *specCache) Load(locatorAddress string) (entry domain.SpecCacheEntry, found bool) {
+	if sc.dir == "" {
+		return
+	}
+	content, err := ioutil.ReadFile(entryPath(sc.dir, locatorAddress))
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(content, &entry)
+	if err != nil || entry.LocatorAddress != locatorAddress {
+		return domain.SpecCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Store writes the entry for a locator, replacing any previous entry
+func (sc *specCache) Store(entry domain.SpecCacheEntry) error {
+	if sc.dir == "" {
+		return nil
+	}
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return writeEntry(sc.dir, entry.LocatorAddress, content)
+}
+
+// TTL is how long an entry is used before it is revalidated
+func (sc *specCache) TTL() time.Duration {
+	return sc.ttl
+}
+
+// entryPath names the file of an entry by the hash of its key
+func entryPath(dir string, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// writeEntry writes to a temporary file first so concurrent invocations never read a partial entry
+func writeEntry(dir string, key string, content []byte) error {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), entryPath(dir, key))
+}
@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/cache"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SpecCache", func() {
+
+	var (
+		dir   string
+		entry domain.SpecCacheEntry
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "spec-cache")
+		Expect(err).NotTo(HaveOccurred())
+		entry = domain.SpecCacheEntry{
+			LocatorAddress: "https://some.geode-locator.com",
+			SpecURL:        "https://some.geode-locator.com/management/v1/api-docs",
+			ETag:           `"abc"`,
+			FetchedAt:      time.Now().Round(time.Second),
+			UseToken:       true,
+			AvailableEndpoints: map[string]domain.RestEndPoint{
+				"list regions": {CommandName: "list regions", HTTPMethod: "get", URL: "/v1/regions"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Context("New", func() {
+		It("Rejects a negative TTL", func() {
+			specCache, err := New(dir, -time.Second)
+			Expect(err).To(HaveOccurred())
+			Expect(specCache).To(BeNil())
+		})
+	})
+
+	Context("An entry has been stored", func() {
+		It("Loads the entry for the same locator", func() {
+			specCache, err := New(dir, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(specCache.Store(entry)).To(Succeed())
+
+			loaded, found := specCache.Load("https://some.geode-locator.com")
+			Expect(found).To(BeTrue())
+			Expect(loaded.SpecURL).To(Equal(entry.SpecURL))
+			Expect(loaded.ETag).To(Equal(entry.ETag))
+			Expect(loaded.FetchedAt.Equal(entry.FetchedAt)).To(BeTrue())
+			Expect(loaded.UseToken).To(BeTrue())
+			Expect(loaded.AvailableEndpoints).To(HaveKey("list regions"))
+			Expect(specCache.TTL()).To(Equal(time.Minute))
+		})
+
+		It("Does not find entries for other locators", func() {
+			specCache, _ := New(dir, time.Minute)
+			Expect(specCache.Store(entry)).To(Succeed())
+
+			_, found := specCache.Load("https://other.geode-locator.com")
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Context("Caching is disabled with an empty directory", func() {
+		It("Stores nothing and finds nothing", func() {
+			specCache, err := New("", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(specCache.Store(entry)).To(Succeed())
+
+			_, found := specCache.Load(entry.LocatorAddress)
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Context("FromEnvironment", func() {
+		AfterEach(func() {
+			os.Unsetenv("GEODE_SPEC_CACHE_DIR")
+			os.Unsetenv("GEODE_SPEC_CACHE_TTL")
+		})
+
+		It("Uses the directory and TTL from the environment", func() {
+			os.Setenv("GEODE_SPEC_CACHE_DIR", dir)
+			os.Setenv("GEODE_SPEC_CACHE_TTL", "10m")
+			specCache, err := FromEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(specCache.TTL()).To(Equal(10 * time.Minute))
+			Expect(specCache.Store(entry)).To(Succeed())
+			files, _ := ioutil.ReadDir(dir)
+			Expect(files).To(HaveLen(1))
+		})
+
+		It("Returns an error for an invalid TTL", func() {
+			os.Setenv("GEODE_SPEC_CACHE_TTL", "soon")
+			_, err := FromEnvironment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("GEODE_SPEC_CACHE_TTL"))
+		})
+	})
+})
@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultValuesTTL is how long values listed from the cluster are used for completion
+const DefaultValuesTTL = 10 * time.Second
+
+type valuesCache struct {
+	dir string
+	ttl time.Duration
+}
+
+type valuesEntry struct {
+	Key      string    `json:"key"`
+	Values   []string  `json:"values"`
+	StoredAt time.Time `json:"storedAt"`
+}
+
+// NewValuesCache provides a constructor for the on-disk implementation of the
+// completion.ValuesCache interface. An empty dir disables caching
+func NewValuesCache(dir string, ttl time.Duration) (*valuesCache, error) {
+	if ttl < 0 {
+		return nil, errors.New("values cache TTL must not be negative")
+	}
+	return &valuesCache{dir: dir, ttl: ttl}, nil
+}
+
+// ValuesFromEnvironment constructs the values cache in the user cache directory, using the
+// 'GEODE_COMPLETION_CACHE_TTL' environment variable or DefaultValuesTTL
+func ValuesFromEnvironment() (*valuesCache, error) {
+	var dir string
+	userCacheDir, err := os.UserCacheDir()
+	if err == nil {
+		dir = filepath.Join(userCacheDir, "gemfire", "completion")
+	}
+	ttl := DefaultValuesTTL
+	if value := os.Getenv("GEODE_COMPLETION_CACHE_TTL"); value != "" {
+		ttl, err = time.ParseDuration(value)
+		if err != nil {
+			return nil, errors.New("invalid GEODE_COMPLETION_CACHE_TTL: " + err.Error())
+		}
+	}
+	return NewValuesCache(dir, ttl)
+}
+
+// Load reads the values stored for a key, unless they are older than the TTL
+func (vc *valuesCache) Load(key string) (values []string, found bool) {
+	if vc.dir == "" {
+		return
+	}
+	content, err := ioutil.ReadFile(entryPath(vc.dir, key))
+	if err != nil {
+		return
+	}
+	var entry valuesEntry
+	err = json.Unmarshal(content, &entry)
+	if err != nil || entry.Key != key || time.Since(entry.StoredAt) > vc.ttl {
+		return nil, false
+	}
+	return entry.Values, true
+}
+
+// Store writes the values for a key, replacing any previous values
+func (vc *valuesCache) Store(key string, values []string) error {
+	if vc.dir == "" {
+		return nil
+	}
+	content, err := json.Marshal(valuesEntry{Key: key, Values: values, StoredAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	return writeEntry(vc.dir, key, content)
+}
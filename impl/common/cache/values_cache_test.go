@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/cache"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValuesCache", func() {
+
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "values-cache")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Rejects a negative TTL", func() {
+		valuesCache, err := NewValuesCache(dir, -time.Second)
+		Expect(err).To(HaveOccurred())
+		Expect(valuesCache).To(BeNil())
+	})
+
+	It("Loads the values stored for the same key within the TTL", func() {
+		valuesCache, err := NewValuesCache(dir, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(valuesCache.Store("list regions", []string{"r1", "r2"})).To(Succeed())
+
+		values, found := valuesCache.Load("list regions")
+		Expect(found).To(BeTrue())
+		Expect(values).To(Equal([]string{"r1", "r2"}))
+
+		_, found = valuesCache.Load("list members")
+		Expect(found).To(BeFalse())
+	})
+
+	It("Does not load values older than the TTL", func() {
+		valuesCache, _ := NewValuesCache(dir, 0)
+		Expect(valuesCache.Store("list regions", []string{"r1"})).To(Succeed())
+
+		_, found := valuesCache.Load("list regions")
+		Expect(found).To(BeFalse())
+	})
+
+	It("Returns an error for an invalid TTL in the environment", func() {
+		os.Setenv("GEODE_COMPLETION_CACHE_TTL", "soon")
+		defer os.Unsetenv("GEODE_COMPLETION_CACHE_TTL")
+		_, err := ValuesFromEnvironment()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("GEODE_COMPLETION_CACHE_TTL"))
+	})
+})
@@ -0,0 +1,334 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/vito/go-interact/interact"
+	"github.com/vito/go-interact/interact/terminal"
+)
+
+// Commands working on the configuration of a cluster as a whole
+const (
+	CommandApply  = "apply"
+	CommandPlan   = "plan"
+	CommandExport = "export-config"
+)
+
+// ApplyUsage describes the options of the apply and plan commands
+const ApplyUsage = "usage: apply -f <manifest> [--yes] | plan -f <manifest>\n" +
+	"\t-f, --file <manifest> the YAML or JSON manifest of the desired configuration, - reads it from stdin\n" +
+	"\t--yes, -y applies the changes without asking for confirmation\n" +
+	"entities missing from the manifest are deleted, only those of the groups listed under 'groups' when the manifest lists any\n" +
+	"plan exits with status 2 when the cluster differs from the manifest"
+
+// ExportUsage describes the options of the export-config command
+const ExportUsage = "usage: export-config [--dir <directory>] [-o yaml|json]\n" +
+	"\t--dir <directory> writes a manifest per group, named after the group and managing only that group, instead of printing the configuration\n" +
+	"\t-o, --output <yaml|json> selects the format of the manifest, by default yaml"
+
+// runtimeCommands list entities which are not part of the configuration, or those of other list commands
+var runtimeCommands = []string{"list members", "list region indexes"}
+
+// commandProcessor adds the commands working on the configuration of a cluster as a whole to the
+// commands of the management API
+type commandProcessor struct {
+	impl.CommandProcessor
+	input   io.Reader
+	confirm func(question string) (bool, error)
+}
+
+// NewCommandProcessor provides a constructor for a command processor which handles the apply, plan
+// and export-config commands and passes the other commands on to the command processor given
+func NewCommandProcessor(comm impl.CommandProcessor) (impl.CommandProcessor, error) {
+	if comm == nil {
+		return nil, errors.New("command processor must not be nil")
+	}
+	return &commandProcessor{CommandProcessor: comm, input: os.Stdin, confirm: confirm}, nil
+}
+
+// ProcessCommand implements the impl.CommandProcessor interface
+func (c *commandProcessor) ProcessCommand(commandData *domain.CommandData) error {
+	switch commandData.UserCommand.Command {
+	case CommandApply, CommandPlan:
+		return c.applyManifest(commandData, commandData.UserCommand.Command == CommandApply)
+	case CommandExport:
+		return c.exportConfig(commandData)
+	}
+	return c.CommandProcessor.ProcessCommand(commandData)
+}
+
+// applyManifest prints the changes needed to bring the cluster in line with a manifest and, unless
+// only planning, makes them after confirmation
+func (c *commandProcessor) applyManifest(commandData *domain.CommandData, apply bool) error {
+	parameters := commandData.UserCommand.Parameters
+	if common.HasOption(parameters, []string{"-h", "--help", "-help"}) {
+		fmt.Println(ApplyUsage)
+		return nil
+	}
+	file := common.GetOption(parameters, []string{"-f", "--file"})
+	if file == "" {
+		return errors.New("The manifest is missing, give it with -f <file>")
+	}
+	desired, groups, err := ReadManifest(file, c.input)
+	if err != nil {
+		return err
+	}
+	err = c.loadEndPoints(commandData)
+	if err != nil {
+		return err
+	}
+	current, err := readCluster(c.CommandProcessor, commandData, desired)
+	if err != nil {
+		return err
+	}
+
+	plan := NewPlan(desired, groups, current)
+	fmt.Println(plan.Describe(commandData))
+	if !apply {
+		if len(plan) > 0 {
+			return &driftError{message: "The cluster differs from the manifest"}
+		}
+		return nil
+	}
+	if len(plan) == 0 {
+		return nil
+	}
+	if unsupported := plan.Unsupported(commandData); len(unsupported) > 0 {
+		return fmt.Errorf("The cluster cannot make %d of the changes, change the manifest or make them by hand first", len(unsupported))
+	}
+	if !common.HasOption(parameters, []string{"--yes", "-y"}) {
+		confirmed, err := c.confirm("Apply these changes?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("No changes applied.")
+			return nil
+		}
+	}
+
+	for _, change := range plan {
+		err = c.makeChange(commandData, change)
+		if err != nil {
+			return &contextError{context: fmt.Sprintf("Unable to %s %s %s", change.Action, change.Type.Kind, change.Key), err: err}
+		}
+		fmt.Printf("%s %s %s: done\n", change.Action, change.Type.Kind, change.Key)
+	}
+	fmt.Printf("Applied %d changes.\n", len(plan))
+	return nil
+}
+
+// exportConfig prints the configuration of the cluster as a manifest, or writes a manifest per group
+func (c *commandProcessor) exportConfig(commandData *domain.CommandData) error {
+	parameters := commandData.UserCommand.Parameters
+	if common.HasOption(parameters, []string{"-h", "--help", "-help"}) {
+		fmt.Println(ExportUsage)
+		return nil
+	}
+	outputFormat := common.GetOption(parameters, []string{"--output", "-o"})
+	if outputFormat == "" {
+		outputFormat = "yaml"
+	}
+	if outputFormat != "yaml" && outputFormat != "json" {
+		return errors.New("The manifest can be exported as yaml or json, not " + outputFormat)
+	}
+	err := c.loadEndPoints(commandData)
+	if err != nil {
+		return err
+	}
+	manifest, err := readCluster(c.CommandProcessor, commandData, nil)
+	if err != nil {
+		return err
+	}
+	if skipped := notExported(commandData); len(skipped) > 0 {
+		fmt.Fprintln(os.Stderr, "Not exported, manifests do not describe the entities of: "+strings.Join(skipped, ", "))
+	}
+
+	dir := common.GetOption(parameters, []string{"--dir"})
+	if dir == "" {
+		content, err := manifest.Marshal(outputFormat, nil)
+		if err != nil {
+			return err
+		}
+		fmt.Println(strings.TrimSuffix(string(content), "\n"))
+		return nil
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	groups := manifest.ByGroup()
+	for _, name := range sortedKeys(groups) {
+		content, err := groups[name].Marshal(outputFormat, []string{name})
+		if err != nil {
+			return err
+		}
+		file := filepath.Join(dir, filepath.Base(name)+"."+outputFormat)
+		err = ioutil.WriteFile(file, content, 0644)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Wrote " + file)
+	}
+	return nil
+}
+
+// notExported lists the list commands of the cluster which read entities unknown to manifests
+func notExported(commandData *domain.CommandData) []string {
+	var skipped []string
+	for name, endpoint := range commandData.AvailableEndpoints {
+		if !strings.HasPrefix(name, "list ") || strings.Contains(endpoint.URL, "/operations/") || common.Contains(runtimeCommands, name) {
+			continue
+		}
+		known := false
+		for _, entityType := range EntityTypes {
+			known = known || entityType.List == name
+		}
+		if !known {
+			skipped = append(skipped, name)
+		}
+	}
+	sort.Strings(skipped)
+	return skipped
+}
+
+// loadEndPoints discovers the commands of the cluster unless they are known already
+func (c *commandProcessor) loadEndPoints(commandData *domain.CommandData) error {
+	if len(commandData.AvailableEndpoints) > 0 && !common.HasOption(commandData.UserCommand.Parameters, []string{"--refresh-spec"}) {
+		return nil
+	}
+	return c.LoadEndPoints(commandData)
+}
+
+// readCluster reads the entities of the types listed in the manifest from the cluster, or without a
+// manifest those of all types the cluster provides
+func readCluster(comm impl.CommandProcessor, commandData *domain.CommandData, manifest Manifest) (Manifest, error) {
+	current := make(Manifest)
+	for _, entityType := range EntityTypes {
+		if _, listed := manifest[entityType.Name]; manifest != nil && !listed {
+			continue
+		}
+		if manifest == nil && !entityType.Available(commandData) {
+			continue
+		}
+		if !entityType.Available(commandData) {
+			return nil, fmt.Errorf("The cluster does not provide '%s', remove %s from the manifest", entityType.readCommand(), entityType.Name)
+		}
+		entities, err := entityType.Read(comm, commandData)
+		if err != nil {
+			return nil, &contextError{context: "Unable to read the " + entityType.Name + " of the cluster", err: err}
+		}
+		current[entityType.Name] = entities
+	}
+	return current, nil
+}
+
+// makeChange runs the command of the management API which makes a change
+func (c *commandProcessor) makeChange(commandData *domain.CommandData, change Change) error {
+	command := change.Command()
+	parameters, err := changeParameters(commandData.AvailableEndpoints[command], change)
+	if err != nil {
+		return err
+	}
+	_, err = c.ExecuteCommand(withCommand(commandData, command, parameters))
+	return err
+}
+
+// changeParameters provides the parameters of the command making a change: the identity of an entity
+// to delete, otherwise its configuration and, for deployments, the file to upload
+func changeParameters(endpoint domain.RestEndPoint, change Change) (map[string]string, error) {
+	if change.Action == ActionDelete {
+		return change.Type.deleteParameters(change.Entity), nil
+	}
+	configuration := make(Entity)
+	for name, value := range change.Entity {
+		if !common.Contains(change.Type.ignored, name) {
+			configuration[name] = value
+		}
+	}
+	content, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := make(map[string]string)
+	for _, parameter := range endpoint.Parameters {
+		switch {
+		case parameter.In == "body", parameter.In == "query" && parameter.Name == "config":
+			parameters["--"+parameter.Name] = string(content)
+		case parameter.In == "formData" && parameter.Type == "file":
+			parameters["--"+parameter.Name] = deploymentFile(change.Entity)
+		}
+	}
+	if len(parameters) == 0 {
+		return nil, fmt.Errorf("The command '%s' does not take the configuration of a %s", endpoint.CommandName, change.Type.Kind)
+	}
+	return parameters, nil
+}
+
+// confirm asks on the terminal whether to go ahead, and fails without one so that scripts do not block
+func confirm(question string) (bool, error) {
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return false, errors.New("Confirmation required, use --yes to apply the changes without a terminal")
+	}
+	interaction := interact.NewInteraction(question)
+	interaction.Output = os.Stderr
+	var confirmed bool
+	err := interaction.Resolve(&confirmed)
+	return confirmed, err
+}
+
+// driftError reports a cluster which differs from the manifest, or from another cluster
+type driftError struct {
+	message string
+}
+
+func (e *driftError) Error() string {
+	return e.message
+}
+
+// ExitCode is the process exit code of configurations which differ
+func (e *driftError) ExitCode() int {
+	return common.ExitCodeDrift
+}
+
+// contextError adds context to a failure, keeping its exit code
+type contextError struct {
+	context string
+	err     error
+}
+
+func (e *contextError) Error() string {
+	return e.context + ": " + e.err.Error()
+}
+
+// ExitCode is the process exit code of the failure
+func (e *contextError) ExitCode() int {
+	return common.ExitCode(e.err)
+}
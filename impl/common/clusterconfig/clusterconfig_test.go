@@ -0,0 +1,219 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/clusterconfig"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/implfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CommandProcessor", func() {
+
+	var (
+		comm             *implfakes.FakeCommandProcessor
+		commandProcessor impl.CommandProcessor
+		commandData      domain.CommandData
+		responses        map[string]string
+		failures         map[string]error
+		executed         []domain.UserCommand
+		dir              string
+	)
+
+	writeManifest := func(content string) string {
+		file := filepath.Join(dir, "cluster.yaml")
+		Expect(ioutil.WriteFile(file, []byte(content), 0600)).To(Succeed())
+		return file
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "clusterconfig")
+		Expect(err).NotTo(HaveOccurred())
+		comm = new(implfakes.FakeCommandProcessor)
+		commandProcessor, err = NewCommandProcessor(comm)
+		Expect(err).NotTo(HaveOccurred())
+
+		executed = nil
+		failures = map[string]error{}
+		responses = map[string]string{
+			"list regions": `{"statusCode": "OK", "result": [
+				{"id": "customers", "groups": [{"configuration": {"name": "customers", "type": "REPLICATE", "links": {"self": "/regions/customers"}}, "runtimeInfo": [{"entryCount": 5}]}]},
+				{"id": "old", "groups": [{"configuration": {"name": "old", "type": "REPLICATE"}}]}]}`,
+			"list disk-stores": `{"statusCode": "OK", "result": [{"id": "ds1", "groups": [{"configuration": {"name": "ds1"}}]}]}`,
+		}
+		comm.LoadEndPointsStub = func(commandData *domain.CommandData) error {
+			commandData.AvailableEndpoints = map[string]domain.RestEndPoint{
+				"list regions":      {CommandName: "list regions"},
+				"list disk-stores":  {CommandName: "list disk-stores"},
+				"get disk-store":    {CommandName: "get disk-store"},
+				"get pdx":           {CommandName: "get pdx"},
+				"configure pdx":     {CommandName: "configure pdx", Parameters: []domain.RestAPIParam{{Name: "pdxType", In: "body"}}},
+				"create region":     {CommandName: "create region", Parameters: []domain.RestAPIParam{{Name: "regionConfig", In: "body"}}},
+				"delete region":     {CommandName: "delete region", Parameters: []domain.RestAPIParam{{Name: "id", In: "path"}, {Name: "group", In: "query"}}},
+				"create disk-store": {CommandName: "create disk-store", Parameters: []domain.RestAPIParam{{Name: "diskStoreConfig", In: "body"}}},
+			}
+			return nil
+		}
+		comm.ExecuteCommandStub = func(commandData *domain.CommandData) (string, error) {
+			executed = append(executed, commandData.UserCommand)
+			if err, failed := failures[commandData.UserCommand.Command]; failed {
+				return "", err
+			}
+			return responses[commandData.UserCommand.Command], nil
+		}
+		commandData = domain.CommandData{UserCommand: domain.UserCommand{Command: "plan", Parameters: map[string]string{}}}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Passes the commands of the management API on", func() {
+		commandData.UserCommand.Command = "list regions"
+		Expect(commandProcessor.ProcessCommand(&commandData)).To(Succeed())
+		Expect(comm.ProcessCommandCallCount()).To(Equal(1))
+	})
+
+	It("Requires a manifest", func() {
+		err := commandProcessor.ProcessCommand(&commandData)
+		Expect(err).To(MatchError(ContainSubstring("The manifest is missing")))
+	})
+
+	Context("plan", func() {
+
+		It("Reports drift with its own exit code", func() {
+			commandData.UserCommand.Parameters["-f"] = writeManifest("regions: [{name: customers, type: REPLICATE}, {name: orders, type: PARTITION}]")
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError("The cluster differs from the manifest"))
+			Expect(common.ExitCode(err)).To(Equal(common.ExitCodeDrift))
+			Expect(executed).To(HaveLen(1))
+			Expect(executed[0].Command).To(Equal("list regions"))
+		})
+
+		It("Succeeds when the cluster matches the manifest", func() {
+			commandData.UserCommand.Parameters["-f"] = writeManifest("regions: [{name: customers, type: REPLICATE}, {name: old}]")
+			Expect(commandProcessor.ProcessCommand(&commandData)).To(Succeed())
+		})
+
+		It("Considers a pdx configuration which is not found absent", func() {
+			failures["get pdx"] = common.NewStatusError(404, "ENTITY_NOT_FOUND")
+			commandData.UserCommand.Parameters["-f"] = writeManifest("pdx: {readSerialized: true}")
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(common.ExitCode(err)).To(Equal(common.ExitCodeDrift))
+		})
+
+		It("Reports entity types the cluster does not provide", func() {
+			commandData.UserCommand.Parameters["-f"] = writeManifest("gatewayReceivers: []")
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError("The cluster does not provide 'list gateway-receivers', remove gatewayReceivers from the manifest"))
+		})
+	})
+
+	Context("apply", func() {
+
+		BeforeEach(func() {
+			commandData.UserCommand.Command = "apply"
+			commandData.UserCommand.Parameters["-f"] = writeManifest(`
+regions:
+  - name: customers
+    type: REPLICATE
+  - name: orders
+    type: PARTITION
+    group: g1
+diskStores:
+  - name: ds1
+  - name: ds2
+`)
+		})
+
+		It("Makes the changes in the order of their dependencies", func() {
+			commandData.UserCommand.Parameters["--yes"] = ""
+			Expect(commandProcessor.ProcessCommand(&commandData)).To(Succeed())
+			Expect(executed[2:]).To(Equal([]domain.UserCommand{
+				{Command: "delete region", Parameters: map[string]string{"--id": "old"}},
+				{Command: "create disk-store", Parameters: map[string]string{"--diskStoreConfig": `{"name":"ds2"}`}},
+				{Command: "create region", Parameters: map[string]string{"--regionConfig": `{"group":"g1","name":"orders","type":"PARTITION"}`}},
+			}))
+		})
+
+		It("Stops at the first failure, keeping its exit code", func() {
+			commandData.UserCommand.Parameters["--yes"] = ""
+			failures["create disk-store"] = common.NewStatusError(409, "ENTITY_EXISTS")
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError("Unable to create disk-store ds2: ENTITY_EXISTS"))
+			Expect(common.ExitCode(err)).To(Equal(common.ExitCodeConflict))
+			Expect(executed).To(HaveLen(4))
+		})
+
+		It("Asks for confirmation, which requires a terminal", func() {
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError(ContainSubstring("use --yes")))
+			Expect(executed).To(HaveLen(2))
+		})
+
+		It("Makes no changes when the cluster cannot make all of them", func() {
+			commandData.UserCommand.Parameters["--yes"] = ""
+			commandData.UserCommand.Parameters["-f"] = writeManifest("regions: [{name: customers, type: PARTITION}, {name: old, type: REPLICATE}]")
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError(ContainSubstring("The cluster cannot make 1 of the changes")))
+			Expect(executed).To(HaveLen(1))
+		})
+	})
+
+	Context("export-config", func() {
+
+		BeforeEach(func() {
+			commandData.UserCommand.Command = "export-config"
+			responses["list disk-stores"] = `{"statusCode": "OK", "result": [{"id": "ds1", "links": {"self": "/diskstores/ds1"}}]}`
+			responses["get disk-store"] = `{"statusCode": "OK", "result": {"id": "ds1", "groups": [{"configuration": {"name": "ds1", "group": "g1", "autoCompact": true}, "runtimeInfo": [{"memberName": "server1"}]}]}}`
+			failures["get pdx"] = common.NewStatusError(404, "ENTITY_NOT_FOUND")
+		})
+
+		It("Writes a manifest per group, getting the details the list omits", func() {
+			commandData.UserCommand.Parameters["--dir"] = filepath.Join(dir, "export")
+			Expect(commandProcessor.ProcessCommand(&commandData)).To(Succeed())
+			Expect(executed).To(ContainElement(domain.UserCommand{Command: "get disk-store", Parameters: map[string]string{"--id": "ds1"}}))
+
+			content, err := ioutil.ReadFile(filepath.Join(dir, "export", "cluster.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("groups:\n- cluster\ndiskStores: []\npdx: null\nregions:\n- name: customers\n  type: REPLICATE\n- name: old\n  type: REPLICATE\n"))
+			content, err = ioutil.ReadFile(filepath.Join(dir, "export", "g1.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("groups:\n- g1\ndiskStores:\n- autoCompact: true\n  group: g1\n  name: ds1\n"))
+
+			manifest, groups, err := ReadManifest(filepath.Join(dir, "export"), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest["regions"]).To(HaveLen(2))
+			Expect(manifest["diskStores"]).To(HaveLen(1))
+			Expect(groups).To(ConsistOf("cluster", "g1"))
+		})
+
+		It("Exports as YAML or JSON only", func() {
+			commandData.UserCommand.Parameters["-o"] = "csv"
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError("The manifest can be exported as yaml or json, not csv"))
+		})
+	})
+})
@@ -0,0 +1,238 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+)
+
+// CommandCopy creates entities of one cluster on another
+const CommandCopy = "copy"
+
+// CopyUsage describes the arguments of the copy command
+const CopyUsage = "usage: copy <" + copyKinds + "> --id <id> --from <target> --to <target> | copy [<" + copyKinds + ">] --all --from <target> --to <target>\n" +
+	"\t--id <id> names the entity to copy, an index by its name or <region>.<name>\n" +
+	"\t--all copies every entity of the type, or of all types when none is given\n" +
+	"\t--from, --to <target> a locator URL or a profile, or a service instance when run as a cf plugin. The other options apply to both targets, except --password-stdin which is rejected\n" +
+	"entities which already exist on the cluster copied to are skipped"
+
+const copyKinds = "region|index|disk-store|gateway-receiver"
+
+// TwoClusterCommands name the commands which work on two clusters, named by their arguments or
+// options instead of the target of the command
+var TwoClusterCommands = []string{CommandDiff, CommandCopy}
+
+// RunTwoClusterCommand runs one of the TwoClusterCommands. The connector provides the connection data
+// of each cluster. '--password-stdin' is rejected since the input can only be read for one of them
+func RunTwoClusterCommand(comm impl.CommandProcessor, connect Connector, userCommand domain.UserCommand) error {
+	if common.HasOption(userCommand.Parameters, []string{"--password-stdin"}) {
+		return errors.New("--password-stdin cannot be used with diff or copy, which connect to two clusters. " +
+			"Use --password-file, or enter each password when prompted")
+	}
+	if strings.HasPrefix(userCommand.Command, CommandCopy) {
+		return Copy(comm, connect, userCommand)
+	}
+	return Diff(comm, connect, userCommand)
+}
+
+// Copy reads the configuration of entities from the cluster named by --from and creates them on the
+// cluster named by --to, skipping those which exist there already
+func Copy(comm impl.CommandProcessor, connect Connector, userCommand domain.UserCommand) error {
+	if comm == nil {
+		return errors.New("command processor must not be nil")
+	}
+	if connect == nil {
+		return errors.New("connector must not be nil")
+	}
+	parameters := userCommand.Parameters
+	if common.HasOption(parameters, []string{"-h", "--help", "-help"}) {
+		fmt.Println(CopyUsage)
+		return nil
+	}
+	words := strings.Fields(userCommand.Command)[1:]
+	id := common.GetOption(parameters, []string{"--id"})
+	all := common.HasOption(parameters, []string{"--all"})
+	from := common.GetOption(parameters, []string{"--from"})
+	to := common.GetOption(parameters, []string{"--to"})
+	if len(words) > 1 || (id != "") == all || (id != "" && len(words) == 0) || from == "" || to == "" {
+		return errors.New(CopyUsage)
+	}
+	types, err := copyTypes(words)
+	if err != nil {
+		return err
+	}
+
+	source, err := connectCluster(comm, connect, from, parameters)
+	if err != nil {
+		return err
+	}
+	destination, err := connectCluster(comm, connect, to, parameters)
+	if err != nil {
+		return err
+	}
+
+	copied, skipped := 0, 0
+	for _, entityType := range types {
+		if !entityType.Available(source) {
+			if len(types) == 1 {
+				return fmt.Errorf("The cluster %s does not provide '%s'", from, entityType.readCommand())
+			}
+			continue
+		}
+		entities, err := entityType.Read(comm, source)
+		if err != nil {
+			return &contextError{context: "Unable to read the " + entityType.Name + " of " + from, err: err}
+		}
+		if !all {
+			entities = matching(entityType, entities, id)
+			if len(entities) == 0 {
+				return common.NewNotFoundError(fmt.Sprintf("No %s %s on %s", entityType.Kind, id, from))
+			}
+		}
+		if len(entities) == 0 {
+			continue
+		}
+		for _, command := range []string{entityType.readCommand(), entityType.Create} {
+			if _, available := destination.AvailableEndpoints[command]; !available {
+				return fmt.Errorf("The cluster %s does not provide '%s'", to, command)
+			}
+		}
+		existing, err := entityType.Read(comm, destination)
+		if err != nil {
+			return &contextError{context: "Unable to read the " + entityType.Name + " of " + to, err: err}
+		}
+		existingByKey := byKey(entityType, existing)
+		entitiesByKey := byKey(entityType, entities)
+		for _, key := range sortedKeys(entitiesByKey) {
+			entity := entitiesByKey[key]
+			// the entity may also have been created since the cluster was read
+			_, exists := existingByKey[key]
+			if !exists {
+				err = createEntity(comm, destination, entityType, key, entity)
+				exists = err != nil && common.ExitCode(err) == common.ExitCodeConflict
+			}
+			if exists {
+				fmt.Printf("skip %s %s: exists on %s\n", entityType.Kind, key, to)
+				skipped++
+				continue
+			}
+			if err != nil {
+				return &contextError{context: fmt.Sprintf("Unable to copy %s %s to %s", entityType.Kind, key, to), err: err}
+			}
+			fmt.Printf("copy %s %s to %s: done\n", entityType.Kind, key, to)
+			copied++
+		}
+	}
+	fmt.Printf("Copied %d, skipped %d which already exist.\n", copied, skipped)
+	return nil
+}
+
+// copyTypes provides the entity type named by its kind, or without one all types copy works on
+func copyTypes(words []string) ([]*EntityType, error) {
+	var types []*EntityType
+	for _, entityType := range EntityTypes {
+		if !common.Contains(diffTypes, entityType.Name) {
+			continue
+		}
+		if len(words) == 0 || words[0] == entityType.Kind || words[0] == entityType.Name {
+			types = append(types, entityType)
+		}
+	}
+	if len(types) == 0 {
+		return nil, errors.New("Unable to copy " + words[0] + ", use one of: " + strings.ReplaceAll(copyKinds, "|", ", "))
+	}
+	return types, nil
+}
+
+// connectCluster provides the connection data and the commands of the cluster named by a target
+func connectCluster(comm impl.CommandProcessor, connect Connector, target string, parameters map[string]string) (*domain.CommandData, error) {
+	commandData := &domain.CommandData{Target: target, UserCommand: domain.UserCommand{Command: CommandCopy, Parameters: copyParameters(parameters)}}
+	err := connect(commandData)
+	if err == nil {
+		err = comm.LoadEndPoints(commandData)
+	}
+	if err != nil {
+		return nil, &contextError{context: "Unable to connect to " + target, err: err}
+	}
+	return commandData, nil
+}
+
+// matching selects the entities identified by an id, their name or their key, e.g. a region in all
+// of its groups
+func matching(entityType *EntityType, entities []Entity, id string) []Entity {
+	var matches []Entity
+	for _, entity := range entities {
+		if entityType.Key(entity) == id || field(entity, "name") == id {
+			matches = append(matches, entity)
+		}
+	}
+	return matches
+}
+
+// createEntity runs the create command of the entity type with the settings of the entity the
+// command accepts
+func createEntity(comm impl.CommandProcessor, commandData *domain.CommandData, entityType *EntityType, key string, entity Entity) error {
+	endpoint := commandData.AvailableEndpoints[entityType.Create]
+	change := Change{Action: ActionCreate, Type: entityType, Key: key, Entity: entityType.accepted(entity, endpoint)}
+	parameters, err := changeParameters(endpoint, change)
+	if err != nil {
+		return err
+	}
+	_, err = comm.ExecuteCommand(withCommand(commandData, entityType.Create, parameters))
+	return err
+}
+
+// accepted keeps the settings of an entity which are in the definition of the body of a create
+// command, so that settings only the cluster read from reports are not sent. Without a definition,
+// all settings are kept
+func (t *EntityType) accepted(entity Entity, endpoint domain.RestEndPoint) Entity {
+	for _, parameter := range endpoint.Parameters {
+		if parameter.In == "body" && len(parameter.BodyDefinition) > 0 {
+			kept := Entity(definedSettings(entity, parameter.BodyDefinition))
+			for _, name := range append(t.keyFields, "group") {
+				if value, set := entity[name]; set {
+					kept[name] = value
+				}
+			}
+			return kept
+		}
+	}
+	return entity
+}
+
+// definedSettings keeps the settings which are in a definition, nested objects by their own definition
+func definedSettings(settings map[string]interface{}, definition map[string]interface{}) map[string]interface{} {
+	kept := make(map[string]interface{})
+	for name, value := range settings {
+		sample, defined := definition[name]
+		if !defined {
+			continue
+		}
+		nestedDefinition, definesObject := sample.(map[string]interface{})
+		nested, isObject := value.(map[string]interface{})
+		if definesObject && isObject {
+			value = definedSettings(nested, nestedDefinition)
+		}
+		kept[name] = value
+	}
+	return kept
+}
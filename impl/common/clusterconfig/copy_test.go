@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig_test
+
+import (
+	"encoding/json"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/clusterconfig"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/implfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Copy", func() {
+
+	var (
+		comm        *implfakes.FakeCommandProcessor
+		responses   map[string]map[string]string
+		failures    map[string]error
+		created     []domain.CommandData
+		connect     Connector
+		userCommand domain.UserCommand
+	)
+
+	copyCommand := func(args ...string) domain.UserCommand {
+		return common.ParseUserCommand(append(args, "--from", "http://prod", "--to", "http://staging"))
+	}
+
+	BeforeEach(func() {
+		comm = new(implfakes.FakeCommandProcessor)
+		created = nil
+		failures = map[string]error{}
+		responses = map[string]map[string]string{
+			"http://prod": {
+				"list regions": `{"statusCode": "OK", "result": [
+					{"id": "customers", "groups": [{"configuration": {"name": "customers", "type": "PARTITION", "redundantCopies": 1, "diskStoreName": "ds1", "links": {"self": "/regions/customers"}}}]},
+					{"id": "orders", "groups": [{"configuration": {"name": "orders", "type": "REPLICATE", "group": "eu"}}]}]}`,
+				"list disk-stores": `{"statusCode": "OK", "result": [{"id": "ds1", "groups": [{"configuration": {"name": "ds1"}}]}]}`,
+			},
+			"http://staging": {
+				"list regions":     `{"statusCode": "OK", "result": [{"id": "orders", "groups": [{"configuration": {"name": "orders", "type": "REPLICATE", "group": "eu"}}]}]}`,
+				"list disk-stores": `{"statusCode": "OK", "result": []}`,
+			},
+		}
+		comm.LoadEndPointsStub = func(commandData *domain.CommandData) error {
+			commandData.AvailableEndpoints = map[string]domain.RestEndPoint{
+				"list regions":     {CommandName: "list regions"},
+				"list disk-stores": {CommandName: "list disk-stores"},
+				"create region": {CommandName: "create region", Parameters: []domain.RestAPIParam{{Name: "regionConfig", In: "body",
+					BodyDefinition: map[string]interface{}{"name": "string-value", "type": "ENUM", "redundantCopies": 42}}}},
+				"create disk-store": {CommandName: "create disk-store", Parameters: []domain.RestAPIParam{{Name: "diskStoreConfig", In: "body"}}},
+			}
+			return nil
+		}
+		comm.ExecuteCommandStub = func(commandData *domain.CommandData) (string, error) {
+			if commandData.UserCommand.Command == "create region" || commandData.UserCommand.Command == "create disk-store" {
+				created = append(created, *commandData)
+			}
+			if err, failed := failures[commandData.UserCommand.Command]; failed {
+				return "", err
+			}
+			return responses[commandData.ConnnectionData.LocatorAddress][commandData.UserCommand.Command], nil
+		}
+		connect = func(commandData *domain.CommandData) error {
+			commandData.ConnnectionData.LocatorAddress = commandData.Target
+			return nil
+		}
+		userCommand = copyCommand("copy", "region", "--id", "customers")
+	})
+
+	It("Requires either an id or --all", func() {
+		Expect(Copy(comm, connect, copyCommand("copy", "region"))).To(MatchError(CopyUsage))
+		Expect(Copy(comm, connect, copyCommand("copy", "region", "--id", "customers", "--all"))).To(MatchError(CopyUsage))
+		Expect(Copy(comm, connect, copyCommand("copy", "--id", "customers"))).To(MatchError(CopyUsage))
+	})
+
+	It("Requires the clusters to copy from and to", func() {
+		Expect(Copy(comm, connect, common.ParseUserCommand([]string{"copy", "region", "--id", "customers", "--from", "http://prod"}))).To(MatchError(CopyUsage))
+	})
+
+	It("Rejects --password-stdin, which only one cluster could read", func() {
+		connections := 0
+		connect = func(commandData *domain.CommandData) error {
+			connections++
+			return nil
+		}
+		for _, command := range []domain.UserCommand{
+			copyCommand("copy", "region", "--id", "customers", "--password-stdin"),
+			common.ParseUserCommand([]string{"diff", "http://prod", "http://staging", "--password-stdin"}),
+		} {
+			err := RunTwoClusterCommand(comm, connect, command)
+			Expect(err).To(MatchError(ContainSubstring("--password-stdin cannot be used with diff or copy")))
+		}
+		Expect(connections).To(BeZero())
+	})
+
+	It("Rejects entity types it does not copy", func() {
+		err := Copy(comm, connect, copyCommand("copy", "pdx", "--all"))
+		Expect(err).To(MatchError("Unable to copy pdx, use one of: region, index, disk-store, gateway-receiver"))
+	})
+
+	It("Creates the entity with the settings the create command accepts", func() {
+		Expect(Copy(comm, connect, userCommand)).To(Succeed())
+		Expect(created).To(HaveLen(1))
+		Expect(created[0].ConnnectionData.LocatorAddress).To(Equal("http://staging"))
+		var body map[string]interface{}
+		Expect(json.Unmarshal([]byte(created[0].UserCommand.Parameters["--regionConfig"]), &body)).To(Succeed())
+		Expect(body).To(Equal(map[string]interface{}{"name": "customers", "type": "PARTITION", "redundantCopies": 1.0}))
+	})
+
+	It("Reports entities which are not found", func() {
+		err := Copy(comm, connect, copyCommand("copy", "region", "--id", "missing"))
+		Expect(err).To(MatchError("No region missing on http://prod"))
+		Expect(common.ExitCode(err)).To(Equal(common.ExitCodeNotFound))
+	})
+
+	It("Skips entities which already exist", func() {
+		Expect(Copy(comm, connect, copyCommand("copy", "region", "--id", "orders"))).To(Succeed())
+		Expect(created).To(BeEmpty())
+	})
+
+	It("Skips entities created since the cluster was read", func() {
+		failures["create region"] = common.NewStatusError(409, "ENTITY_EXISTS")
+		Expect(Copy(comm, connect, userCommand)).To(Succeed())
+	})
+
+	It("Reports failures to create an entity", func() {
+		failures["create region"] = common.NewStatusError(500, "ERROR: no disk store ds1")
+		err := Copy(comm, connect, userCommand)
+		Expect(err).To(MatchError("Unable to copy region customers to http://staging: ERROR: no disk store ds1"))
+		Expect(common.ExitCode(err)).To(Equal(common.ExitCodeServerError))
+	})
+
+	It("Copies all entities of all types in the order of their dependencies", func() {
+		Expect(Copy(comm, connect, copyCommand("copy", "--all"))).To(Succeed())
+		Expect(created).To(HaveLen(2))
+		Expect(created[0].UserCommand.Command).To(Equal("create disk-store"))
+		Expect(created[1].UserCommand.Command).To(Equal("create region"))
+	})
+})
@@ -0,0 +1,270 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/vito/go-interact/interact/terminal"
+)
+
+// CommandDiff compares the configuration of two clusters
+const CommandDiff = "diff"
+
+// DiffUsage describes the arguments of the diff command
+const DiffUsage = "usage: diff <target> <target> [--no-color]\n" +
+	"\ttarget: a locator URL or a profile, or a service instance when run as a cf plugin. The other options apply to both targets, except --password-stdin which is rejected\n" +
+	"\t--no-color prints the differences without color, which is the default when the output is not a terminal\n" +
+	"diff exits with status 2 when the configurations differ"
+
+// diffTypes name the entity types diff compares
+var diffTypes = []string{"diskStores", "regions", "indexes", "gatewayReceivers"}
+
+// ANSI escape sequences coloring the differences
+const (
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorReset  = "\033[0m"
+)
+
+// Connector provides the connection data of the target of a command
+type Connector func(commandData *domain.CommandData) error
+
+// FieldDifference is a setting with different values on the two clusters. The path of nested
+// settings is separated by dots
+type FieldDifference struct {
+	Path  string
+	Left  interface{}
+	Right interface{}
+}
+
+// Difference is an entity configured differently on the two clusters
+type Difference struct {
+	Type *EntityType
+	Key  string
+	// Left and Right are the configurations of the entity, nil on the cluster without it
+	Left   Entity
+	Right  Entity
+	Fields []FieldDifference
+}
+
+// Differences lists the differences by entity type, in the order of the dependencies of the types
+type Differences []Difference
+
+// Diff prints the differences between the configuration of the two clusters named as the targets of
+// the command. The connector provides the connection data of each target
+func Diff(comm impl.CommandProcessor, connect Connector, userCommand domain.UserCommand) error {
+	if comm == nil {
+		return errors.New("command processor must not be nil")
+	}
+	if connect == nil {
+		return errors.New("connector must not be nil")
+	}
+	if common.HasOption(userCommand.Parameters, []string{"-h", "--help", "-help"}) {
+		fmt.Println(DiffUsage)
+		return nil
+	}
+	targets := strings.Fields(userCommand.Command)[1:]
+	if len(targets) != 2 {
+		return errors.New(DiffUsage)
+	}
+
+	var clusters [2]*domain.CommandData
+	for i, target := range targets {
+		clusters[i] = &domain.CommandData{Target: target, UserCommand: domain.UserCommand{Command: CommandDiff, Parameters: copyParameters(userCommand.Parameters)}}
+		err := connect(clusters[i])
+		if err == nil {
+			err = comm.LoadEndPoints(clusters[i])
+		}
+		if err != nil {
+			return &contextError{context: "Unable to connect to " + target, err: err}
+		}
+	}
+
+	compared := make(Manifest)
+	for _, name := range diffTypes {
+		entityType, _ := EntityTypeNamed(name)
+		left, right := entityType.Available(clusters[0]), entityType.Available(clusters[1])
+		switch {
+		case left && right:
+			compared[name] = nil
+		case left || right:
+			missing := targets[0]
+			if left {
+				missing = targets[1]
+			}
+			fmt.Fprintf(os.Stderr, "Not compared, %s does not provide '%s'\n", missing, entityType.readCommand())
+		}
+	}
+	var manifests [2]Manifest
+	for i, target := range targets {
+		manifest, err := readCluster(comm, clusters[i], compared)
+		if err != nil {
+			return &contextError{context: "Unable to compare " + target, err: err}
+		}
+		manifests[i] = manifest
+	}
+
+	differences := Compare(manifests[0], manifests[1])
+	colored := !common.HasOption(userCommand.Parameters, []string{"--no-color"}) && os.Getenv("NO_COLOR") == "" &&
+		terminal.IsTerminal(int(os.Stdout.Fd()))
+	fmt.Println(differences.Describe(targets[0], targets[1], colored))
+	if len(differences) > 0 {
+		return &driftError{message: "The configurations differ"}
+	}
+	return nil
+}
+
+// Compare matches the entities of the types listed in both manifests by their identity and lists
+// those missing from one of them or with different settings
+func Compare(left Manifest, right Manifest) Differences {
+	var differences Differences
+	for _, entityType := range EntityTypes {
+		leftEntities, leftListed := left[entityType.Name]
+		rightEntities, rightListed := right[entityType.Name]
+		if !leftListed || !rightListed {
+			continue
+		}
+		leftByKey := byKey(entityType, leftEntities)
+		rightByKey := byKey(entityType, rightEntities)
+		keys := make(map[string]bool)
+		for key := range leftByKey {
+			keys[key] = true
+		}
+		for key := range rightByKey {
+			keys[key] = true
+		}
+		for _, key := range sortedKeys(keys) {
+			difference := Difference{Type: entityType, Key: key, Left: leftByKey[key], Right: rightByKey[key]}
+			if difference.Left != nil && difference.Right != nil {
+				difference.Fields = entityType.compareBoth(difference.Left, difference.Right)
+				if len(difference.Fields) == 0 {
+					continue
+				}
+			}
+			differences = append(differences, difference)
+		}
+	}
+	return differences
+}
+
+// compareBoth lists the settings of either entity which differ from those of the other
+func (t *EntityType) compareBoth(left Entity, right Entity) []FieldDifference {
+	names := make(map[string]bool)
+	for name := range left {
+		names[name] = true
+	}
+	for name := range right {
+		names[name] = true
+	}
+	var fields []FieldDifference
+	for _, name := range sortedKeys(names) {
+		if common.Contains(t.keyFields, name) || common.Contains(t.ignored, name) {
+			continue
+		}
+		fields = compareBothValues(name, left[name], right[name], fields)
+	}
+	return fields
+}
+
+// compareBothValues compares nested objects setting by setting, and other values as a whole
+func compareBothValues(path string, left interface{}, right interface{}, fields []FieldDifference) []FieldDifference {
+	leftObject, leftIsObject := left.(map[string]interface{})
+	rightObject, rightIsObject := right.(map[string]interface{})
+	if leftIsObject && rightIsObject {
+		names := make(map[string]bool)
+		for name := range leftObject {
+			names[name] = true
+		}
+		for name := range rightObject {
+			names[name] = true
+		}
+		for _, name := range sortedKeys(names) {
+			fields = compareBothValues(path+"."+name, leftObject[name], rightObject[name], fields)
+		}
+		return fields
+	}
+	if !reflect.DeepEqual(left, right) {
+		fields = append(fields, FieldDifference{Path: path, Left: left, Right: right})
+	}
+	return fields
+}
+
+// Describe presents the differences: entities only on the left cluster with a -, those only on the
+// right one with a + and those configured differently with a ~ followed by the settings which differ
+func (d Differences) Describe(left string, right string, colored bool) string {
+	if len(d) == 0 {
+		return "No differences, the configurations match."
+	}
+	paint := func(color string, line string) string {
+		if !colored {
+			return line
+		}
+		return color + line + colorReset
+	}
+
+	var description strings.Builder
+	fmt.Fprintln(&description, paint(colorRed, "--- "+left))
+	fmt.Fprintln(&description, paint(colorGreen, "+++ "+right))
+	var leftOnly, rightOnly, changed int
+	for _, difference := range d {
+		switch {
+		case difference.Right == nil:
+			leftOnly++
+			fmt.Fprintln(&description, paint(colorRed, fmt.Sprintf("- %s %s", difference.Type.Kind, difference.Key)))
+			difference.describeSettings(&description, difference.Left, func(line string) string { return paint(colorRed, line) })
+		case difference.Left == nil:
+			rightOnly++
+			fmt.Fprintln(&description, paint(colorGreen, fmt.Sprintf("+ %s %s", difference.Type.Kind, difference.Key)))
+			difference.describeSettings(&description, difference.Right, func(line string) string { return paint(colorGreen, line) })
+		default:
+			changed++
+			fmt.Fprintln(&description, paint(colorYellow, fmt.Sprintf("~ %s %s", difference.Type.Kind, difference.Key)))
+			for _, field := range difference.Fields {
+				fmt.Fprintf(&description, "      %s: %s -> %s\n", field.Path,
+					paint(colorRed, valueString(field.Left)), paint(colorGreen, valueString(field.Right)))
+			}
+		}
+	}
+	fmt.Fprintf(&description, "\n%d only on %s, %d only on %s, %d configured differently.", leftOnly, left, rightOnly, right, changed)
+	return description.String()
+}
+
+func (d Difference) describeSettings(description *strings.Builder, entity Entity, paint func(string) string) {
+	for _, name := range sortedKeys(entity) {
+		if !common.Contains(d.Type.keyFields, name) && !common.Contains(d.Type.ignored, name) {
+			fmt.Fprintln(description, paint(fmt.Sprintf("      %s: %s", name, valueString(entity[name]))))
+		}
+	}
+}
+
+// copyParameters gives each target its own options, so that the settings of one target, e.g. those
+// of a profile, do not apply to the other
+func copyParameters(parameters map[string]string) map[string]string {
+	copied := make(map[string]string, len(parameters))
+	for name, value := range parameters {
+		copied[name] = value
+	}
+	return copied
+}
@@ -0,0 +1,179 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig_test
+
+import (
+	"errors"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/clusterconfig"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/implfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+
+	var (
+		comm        *implfakes.FakeCommandProcessor
+		responses   map[string]map[string]string
+		connected   []*domain.CommandData
+		connect     Connector
+		userCommand domain.UserCommand
+	)
+
+	BeforeEach(func() {
+		comm = new(implfakes.FakeCommandProcessor)
+		connected = nil
+		responses = map[string]map[string]string{
+			"http://a": {
+				"list regions": `{"statusCode": "OK", "result": [
+					{"id": "customers", "groups": [{"configuration": {"name": "customers", "type": "REPLICATE", "links": {"self": "/regions/customers"}}}]},
+					{"id": "old", "groups": [{"configuration": {"name": "old", "type": "PARTITION"}}]}]}`,
+				"list disk-stores": `{"statusCode": "OK", "result": [{"id": "ds1", "groups": [{"configuration": {"name": "ds1", "maxOplogSizeInBytes": 1024}}]}]}`,
+			},
+			"http://b": {
+				"list regions": `{"statusCode": "OK", "result": [
+					{"id": "customers", "groups": [{"configuration": {"name": "customers", "type": "PARTITION"}}]},
+					{"id": "orders", "groups": [{"configuration": {"name": "orders", "type": "PARTITION"}}]}]}`,
+				"list disk-stores": `{"statusCode": "OK", "result": [{"id": "ds1", "groups": [{"configuration": {"name": "ds1", "maxOplogSizeInBytes": 1024}}]}]}`,
+			},
+		}
+		comm.LoadEndPointsStub = func(commandData *domain.CommandData) error {
+			commandData.AvailableEndpoints = map[string]domain.RestEndPoint{
+				"list regions":     {CommandName: "list regions"},
+				"list disk-stores": {CommandName: "list disk-stores"},
+			}
+			return nil
+		}
+		comm.ExecuteCommandStub = func(commandData *domain.CommandData) (string, error) {
+			return responses[commandData.ConnnectionData.LocatorAddress][commandData.UserCommand.Command], nil
+		}
+		connect = func(commandData *domain.CommandData) error {
+			connected = append(connected, commandData)
+			commandData.ConnnectionData.LocatorAddress = commandData.Target
+			return nil
+		}
+		userCommand = common.ParseUserCommand([]string{"diff", "http://a", "http://b", "--no-color"})
+	})
+
+	It("Requires two targets", func() {
+		userCommand.Command = "diff http://a"
+		Expect(Diff(comm, connect, userCommand)).To(MatchError(DiffUsage))
+	})
+
+	It("Connects to each target with its own options", func() {
+		userCommand.Parameters["-u"] = "admin"
+		_ = Diff(comm, connect, userCommand)
+		Expect(connected).To(HaveLen(2))
+		Expect(connected[0].Target).To(Equal("http://a"))
+		Expect(connected[1].Target).To(Equal("http://b"))
+		connected[0].UserCommand.Parameters["--token"] = "secret"
+		Expect(connected[1].UserCommand.Parameters).To(Equal(map[string]string{"-u": "admin", "--no-color": ""}))
+	})
+
+	It("Reports differing configurations with the drift exit code", func() {
+		err := Diff(comm, connect, userCommand)
+		Expect(err).To(MatchError("The configurations differ"))
+		Expect(common.ExitCode(err)).To(Equal(common.ExitCodeDrift))
+	})
+
+	It("Succeeds when the configurations match", func() {
+		responses["http://b"] = responses["http://a"]
+		Expect(Diff(comm, connect, userCommand)).To(Succeed())
+	})
+
+	It("Reports the target it cannot connect to", func() {
+		connect = func(commandData *domain.CommandData) error {
+			return common.NewNetworkError("connection refused")
+		}
+		err := Diff(comm, connect, userCommand)
+		Expect(err).To(MatchError("Unable to connect to http://a: connection refused"))
+		Expect(common.ExitCode(err)).To(Equal(common.ExitCodeNetwork))
+	})
+
+	It("Reports failures to read a cluster", func() {
+		comm.ExecuteCommandStub = func(commandData *domain.CommandData) (string, error) {
+			return "", errors.New("boom")
+		}
+		err := Diff(comm, connect, userCommand)
+		Expect(err).To(MatchError(ContainSubstring("Unable to compare http://a")))
+	})
+})
+
+var _ = Describe("Compare", func() {
+
+	left := Manifest{
+		"regions": {
+			{"name": "customers", "type": "REPLICATE", "expiration": map[string]interface{}{"timeInSeconds": 10.0}},
+			{"name": "old", "type": "PARTITION"},
+		},
+		"diskStores": {{"name": "ds1"}},
+	}
+	right := Manifest{
+		"regions": {
+			{"name": "customers", "type": "REPLICATE", "expiration": map[string]interface{}{"timeInSeconds": 20.0, "action": "DESTROY"}},
+			{"name": "orders", "type": "PARTITION", "groups": []interface{}{"eu"}, "group": "eu"},
+		},
+		"diskStores": {{"name": "ds1"}},
+	}
+
+	It("Matches entities by their identity", func() {
+		differences := Compare(left, right)
+		Expect(differences).To(HaveLen(3))
+		Expect(differences[0].Key).To(Equal("customers"))
+		Expect(differences[0].Fields).To(Equal([]FieldDifference{
+			{Path: "expiration.action", Left: nil, Right: "DESTROY"},
+			{Path: "expiration.timeInSeconds", Left: 10.0, Right: 20.0},
+		}))
+		Expect(differences[1].Key).To(Equal("old"))
+		Expect(differences[1].Right).To(BeNil())
+		Expect(differences[2].Key).To(Equal("orders@eu"))
+		Expect(differences[2].Left).To(BeNil())
+	})
+
+	It("Only compares the types listed in both manifests", func() {
+		Expect(Compare(Manifest{"regions": left["regions"]}, Manifest{"diskStores": nil})).To(BeEmpty())
+	})
+
+	It("Describes the differences", func() {
+		description := Compare(left, right).Describe("http://a", "http://b", false)
+		Expect(description).To(Equal(`--- http://a
++++ http://b
+~ region customers
+      expiration.action: <none> -> DESTROY
+      expiration.timeInSeconds: 10 -> 20
+- region old
+      type: PARTITION
++ region orders@eu
+      groups: ["eu"]
+      type: PARTITION
+
+1 only on http://a, 1 only on http://b, 1 configured differently.`))
+	})
+
+	It("Colors the differences", func() {
+		description := Compare(left, right).Describe("http://a", "http://b", true)
+		Expect(description).To(ContainSubstring("\033[31m- region old\033[0m"))
+		Expect(description).To(ContainSubstring("\033[32m+ region orders@eu\033[0m"))
+		Expect(description).To(ContainSubstring("\033[33m~ region customers\033[0m"))
+	})
+
+	It("Reports matching configurations", func() {
+		Expect(Compare(left, left).Describe("http://a", "http://b", false)).To(Equal("No differences, the configurations match."))
+	})
+})
@@ -0,0 +1,285 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+)
+
+// Entity is the configuration of a region, index or other entity of a cluster, as JSON values
+type Entity map[string]interface{}
+
+// clusterGroup is the group of the entities that apply to the whole cluster
+const clusterGroup = "cluster"
+
+// EntityType describes a kind of entity and the commands of the management API handling it
+type EntityType struct {
+	// Name is the key of the entities in a manifest
+	Name string
+	// Kind names a single entity in messages
+	Kind string
+	// List reads all entities, or Get the single entity of a singleton
+	List string
+	Get  string
+	// Detail gets an entity by its id when the list only identifies it
+	Detail string
+	// Create, Update and Delete change the entities, empty when the API does not provide it
+	Create string
+	Update string
+	Delete string
+	// Singleton marks types with at most one entity per cluster, e.g. pdx
+	Singleton bool
+	// keyFields identify an entity, so they are not compared as settings
+	keyFields []string
+	// ignored are fields which are not part of the configuration on the cluster
+	ignored []string
+	// key identifies an entity among those of its type
+	key func(Entity) string
+	// deleteParameters are the parameters of the delete command for an entity
+	deleteParameters func(Entity) map[string]string
+}
+
+// EntityTypes lists the entity types in the order of their dependencies, which is the order in
+// which they are created
+var EntityTypes = []*EntityType{
+	{
+		Name: "deployments", Kind: "deployment", List: "list deployed", Detail: "get deployed", Create: "deploy",
+		ignored:   []string{"file", "deployedTime", "deployedBy"},
+		keyFields: []string{"group", "fileName", "jarFileName"},
+		key:       func(e Entity) string { return withGroup(filepath.Base(deploymentFile(e)), e) },
+	},
+	{
+		Name: "diskStores", Kind: "disk-store", List: "list disk-stores", Detail: "get disk-store", Create: "create disk-store", Delete: "delete disk-store",
+		keyFields:        []string{"name", "group"},
+		key:              func(e Entity) string { return withGroup(field(e, "name"), e) },
+		deleteParameters: func(e Entity) map[string]string { return groupParameters(e, "--id", field(e, "name")) },
+	},
+	{
+		Name: "pdx", Kind: "pdx", Get: "get pdx", Create: "configure pdx", Update: "update pdx", Delete: "delete pdx", Singleton: true,
+		key:              func(e Entity) string { return "pdx" },
+		deleteParameters: func(e Entity) map[string]string { return map[string]string{} },
+	},
+	{
+		Name: "regions", Kind: "region", List: "list regions", Detail: "get region", Create: "create region", Delete: "delete region",
+		keyFields:        []string{"name", "group"},
+		key:              func(e Entity) string { return withGroup(field(e, "name"), e) },
+		deleteParameters: func(e Entity) map[string]string { return groupParameters(e, "--id", field(e, "name")) },
+	},
+	{
+		Name: "indexes", Kind: "index", List: "list indexes", Create: "create index", Delete: "delete region index",
+		keyFields: []string{"name", "regionPath"},
+		key:       func(e Entity) string { return regionName(e) + "." + field(e, "name") },
+		deleteParameters: func(e Entity) map[string]string {
+			return map[string]string{"--regionName": regionName(e), "--indexName": field(e, "name")}
+		},
+	},
+	{
+		Name: "gatewayReceivers", Kind: "gateway-receiver", List: "list gateway-receivers", Detail: "get gateway-receiver", Create: "create gateway-receiver",
+		keyFields: []string{"group"},
+		key:       func(e Entity) string { return group(e) },
+	},
+}
+
+// runtimeFields are reported by the cluster but are not part of the configuration of an entity
+var runtimeFields = []string{"links", "class", "id", "runtimeInfo"}
+
+// EntityTypeNamed provides the entity type with a manifest key
+func EntityTypeNamed(name string) (*EntityType, bool) {
+	for _, entityType := range EntityTypes {
+		if entityType.Name == name {
+			return entityType, true
+		}
+	}
+	return nil, false
+}
+
+// Key identifies an entity among those of its type
+func (t *EntityType) Key(entity Entity) string {
+	return t.key(entity)
+}
+
+// Available reports whether the cluster provides the command reading the entities of the type
+func (t *EntityType) Available(commandData *domain.CommandData) bool {
+	_, available := commandData.AvailableEndpoints[t.readCommand()]
+	return available
+}
+
+func (t *EntityType) readCommand() string {
+	if t.Singleton {
+		return t.Get
+	}
+	return t.List
+}
+
+// Read lists the entities of the type configured on the cluster, getting the details of those the
+// list only identifies
+func (t *EntityType) Read(comm impl.CommandProcessor, commandData *domain.CommandData) ([]Entity, error) {
+	results, err := t.execute(comm, commandData, t.readCommand(), map[string]string{})
+	if err != nil {
+		// a singleton which is not configured is not found
+		if clusterError, ok := err.(*common.ClusterError); ok && t.Singleton && clusterError.ExitCode() == common.ExitCodeNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entities []Entity
+	for _, result := range results {
+		listed := t.entities(result)
+		id, _ := result["id"].(string)
+		if len(listed) == 0 && id != "" && t.Detail != "" {
+			if _, available := commandData.AvailableEndpoints[t.Detail]; available {
+				details, err := t.execute(comm, commandData, t.Detail, map[string]string{"--id": id})
+				if err != nil {
+					return nil, err
+				}
+				for _, detail := range details {
+					listed = append(listed, t.entities(detail)...)
+				}
+			}
+		}
+		entities = append(entities, listed...)
+	}
+	return entities, nil
+}
+
+// execute runs a list or get command and provides its results
+func (t *EntityType) execute(comm impl.CommandProcessor, commandData *domain.CommandData, command string, parameters map[string]string) ([]map[string]interface{}, error) {
+	urlResponse, err := comm.ExecuteCommand(withCommand(commandData, command, parameters))
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Result interface{} `json:"result"`
+	}
+	err = json.Unmarshal([]byte(urlResponse), &response)
+	if err != nil {
+		return nil, err
+	}
+	results, isList := response.Result.([]interface{})
+	if !isList {
+		results = []interface{}{response.Result}
+	}
+	var objects []map[string]interface{}
+	for _, result := range results {
+		if object, ok := result.(map[string]interface{}); ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// entities provides the configurations of a listed entity without the fields which only identify
+// it, leaving none when the list omits the configuration
+func (t *EntityType) entities(result map[string]interface{}) []Entity {
+	var entities []Entity
+	for _, configuration := range configurations(result) {
+		if entity := t.normalize(configuration); len(entity) > 0 {
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+// configurations extracts the configuration of each group of a listed entity. Older versions of the
+// management API list the configuration without groups
+func configurations(entityInfo map[string]interface{}) []map[string]interface{} {
+	if groups, ok := entityInfo["groups"].([]interface{}); ok {
+		var configurations []map[string]interface{}
+		for _, groupInfo := range groups {
+			if groupInfo, ok := groupInfo.(map[string]interface{}); ok {
+				if configuration, ok := groupInfo["configuration"].(map[string]interface{}); ok {
+					configurations = append(configurations, configuration)
+				}
+			}
+		}
+		return configurations
+	}
+	for _, name := range []string{"configuration", "config"} {
+		if configuration, ok := entityInfo[name].(map[string]interface{}); ok {
+			return []map[string]interface{}{configuration}
+		}
+	}
+	return []map[string]interface{}{entityInfo}
+}
+
+// normalize removes the fields reported by the cluster which are not part of the configuration
+func (t *EntityType) normalize(configuration map[string]interface{}) Entity {
+	entity := make(Entity)
+	for name, value := range configuration {
+		if value != nil && !common.Contains(runtimeFields, name) && !common.Contains(t.ignored, name) {
+			entity[name] = value
+		}
+	}
+	return entity
+}
+
+// withCommand provides a copy of the command data to run another command against the same cluster
+func withCommand(commandData *domain.CommandData, command string, parameters map[string]string) *domain.CommandData {
+	copied := *commandData
+	copied.UserCommand = domain.UserCommand{Command: command, Parameters: parameters}
+	return &copied
+}
+
+func field(entity Entity, name string) string {
+	value, _ := entity[name].(string)
+	return value
+}
+
+// group provides the group of an entity, the cluster group when none is set
+func group(entity Entity) string {
+	if value := field(entity, "group"); value != "" {
+		return value
+	}
+	return clusterGroup
+}
+
+func withGroup(name string, entity Entity) string {
+	if group(entity) == clusterGroup {
+		return name
+	}
+	return name + "@" + group(entity)
+}
+
+func groupParameters(entity Entity, idParameter string, id string) map[string]string {
+	parameters := map[string]string{idParameter: id}
+	if group(entity) != clusterGroup {
+		parameters["--group"] = group(entity)
+	}
+	return parameters
+}
+
+// regionName provides the region of an index, given as a path or a name
+func regionName(entity Entity) string {
+	return strings.TrimPrefix(field(entity, "regionPath"), "/")
+}
+
+// deploymentFile provides the jar of a deployment, the local file in a manifest or the name of the
+// deployed file on the cluster
+func deploymentFile(entity Entity) string {
+	if file := field(entity, "file"); file != "" {
+		return file
+	}
+	if file := field(entity, "fileName"); file != "" {
+		return file
+	}
+	return field(entity, "jarFileName")
+}
@@ -0,0 +1,284 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"gopkg.in/yaml.v2"
+)
+
+// Manifest is the configuration of a cluster, the entities of each type keyed by the name of the type
+type Manifest map[string][]Entity
+
+// groupsKey names the groups a manifest manages, so that entities of other groups are not deleted
+const groupsKey = "groups"
+
+// manifestExtensions are the file extensions of the manifests read from a directory
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// ReadManifest reads a YAML or JSON manifest from a file, from the input when the file is '-', or
+// combines the manifests in a directory, e.g. those of each group. The files of deployments are
+// relative to the manifest. The groups are those the manifest manages, nil when it manages all groups
+func ReadManifest(file string, input io.Reader) (Manifest, []string, error) {
+	if file == "-" {
+		content, err := ioutil.ReadAll(input)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read the manifest from stdin: %s", err)
+		}
+		manifest, groups, err := ParseManifest(content)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid manifest on stdin: %s", err)
+		}
+		return manifest, groups, nil
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read the manifest %s: %s", file, err)
+	}
+	if !info.IsDir() {
+		return readManifestFile(file)
+	}
+
+	files, err := ioutil.ReadDir(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read the manifests in %s: %s", file, err)
+	}
+	combined := make(Manifest)
+	combinedGroups := []string{}
+	for _, info := range files {
+		if info.IsDir() || !common.Contains(manifestExtensions, filepath.Ext(info.Name())) {
+			continue
+		}
+		manifest, groups, err := readManifestFile(filepath.Join(file, info.Name()))
+		if err != nil {
+			return nil, nil, err
+		}
+		for name, entities := range manifest {
+			entityType, _ := EntityTypeNamed(name)
+			if entityType.Singleton && len(entities) > 0 && len(combined[name]) > 0 {
+				return nil, nil, fmt.Errorf("%s is given in more than one manifest in %s", name, file)
+			}
+			combined[name] = append(combined[name], entities...)
+		}
+		// a manifest which does not name its groups manages all of them
+		if groups == nil || combinedGroups == nil {
+			combinedGroups = nil
+		} else {
+			combinedGroups = append(combinedGroups, groups...)
+		}
+	}
+	if len(combined) == 0 {
+		return nil, nil, fmt.Errorf("no manifests in %s", file)
+	}
+	return combined, combinedGroups, nil
+}
+
+func readManifestFile(file string) (Manifest, []string, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read the manifest %s: %s", file, err)
+	}
+	manifest, groups, err := ParseManifest(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid manifest %s: %s", file, err)
+	}
+	manifest.resolveFiles(filepath.Dir(file))
+	return manifest, groups, nil
+}
+
+// ParseManifest parses a YAML or JSON manifest. Singletons such as pdx are given as a single object.
+// The groups are those listed under 'groups', nil when the manifest does not list any and so manages
+// all groups
+func ParseManifest(content []byte) (Manifest, []string, error) {
+	var document map[string]interface{}
+	err := yaml.Unmarshal(content, &document)
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest := make(Manifest)
+	var groups []string
+	for name, value := range document {
+		if name == groupsKey {
+			err = convert(value, &groups)
+			if err != nil || len(groups) == 0 {
+				return nil, nil, fmt.Errorf("%s must be a list of group names, '%s' for the cluster group", groupsKey, clusterGroup)
+			}
+			continue
+		}
+		entityType, known := EntityTypeNamed(name)
+		if !known {
+			return nil, nil, fmt.Errorf("unknown entity type %s, use one of: %s", name, strings.Join(entityTypeNames(), ", "))
+		}
+		if entityType.Singleton && value != nil {
+			value = []interface{}{value}
+		}
+		var entities []Entity
+		err = convert(value, &entities)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s must be a list of objects", name)
+		}
+		if entities == nil {
+			entities = []Entity{}
+		}
+		for _, entity := range entities {
+			if entityType.Key(entity) == "" {
+				return nil, nil, fmt.Errorf("%s must identify each %s", name, entityType.Kind)
+			}
+		}
+		manifest[name] = entities
+	}
+	return manifest, groups, nil
+}
+
+// ByGroup splits the manifest into one per group. The types without groups, e.g. indexes and pdx, are
+// in the manifest of the cluster group, which lists every type of the manifest so that applying the
+// manifests together manages the same types. Each manifest manages its own group only
+func (m Manifest) ByGroup() map[string]Manifest {
+	groups := map[string]Manifest{clusterGroup: {}}
+	for name, entities := range m {
+		groups[clusterGroup][name] = []Entity{}
+		for _, entity := range entities {
+			entityGroup := group(entity)
+			if groups[entityGroup] == nil {
+				groups[entityGroup] = make(Manifest)
+			}
+			groups[entityGroup][name] = append(groups[entityGroup][name], entity)
+		}
+	}
+	return groups
+}
+
+// Marshal renders the manifest as YAML, or JSON for the json output format. The entity types are in
+// the order of their dependencies and the entities are sorted, so that exports can be compared. The
+// groups the manifest manages are listed first unless they are nil, for all groups
+func (m Manifest) Marshal(outputFormat string, groups []string) ([]byte, error) {
+	document := yaml.MapSlice{}
+	object := make(map[string]interface{})
+	if groups != nil {
+		document = append(document, yaml.MapItem{Key: groupsKey, Value: groups})
+		object[groupsKey] = groups
+	}
+	for _, entityType := range EntityTypes {
+		entities, listed := m[entityType.Name]
+		if !listed {
+			continue
+		}
+		sorted := make([]interface{}, 0, len(entities))
+		keyed := byKey(entityType, entities)
+		for _, key := range sortedKeys(keyed) {
+			sorted = append(sorted, integers(map[string]interface{}(keyed[key])))
+		}
+		var value interface{} = sorted
+		if entityType.Singleton {
+			// an unconfigured singleton is null
+			value = nil
+			if len(sorted) > 0 {
+				value = sorted[0]
+			}
+		}
+		document = append(document, yaml.MapItem{Key: entityType.Name, Value: value})
+		object[entityType.Name] = value
+	}
+	if outputFormat == "json" {
+		return json.MarshalIndent(object, "", "  ")
+	}
+	return yaml.Marshal(document)
+}
+
+// integers presents whole numbers decoded from JSON as integers, e.g. sizes in bytes which would
+// otherwise be written in exponent form
+func integers(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case float64:
+		if typed == math.Trunc(typed) && math.Abs(typed) < 1e15 {
+			return int64(typed)
+		}
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			object[key] = integers(item)
+		}
+		return object
+	case []interface{}:
+		list := make([]interface{}, len(typed))
+		for index, item := range typed {
+			list[index] = integers(item)
+		}
+		return list
+	}
+	return value
+}
+
+// resolveFiles makes the files of deployments relative to the directory of the manifest
+func (m Manifest) resolveFiles(dir string) {
+	for _, entity := range m["deployments"] {
+		if file := field(entity, "file"); file != "" && !filepath.IsAbs(file) {
+			entity["file"] = filepath.Join(dir, file)
+		}
+	}
+}
+
+// convert turns decoded YAML into JSON values, e.g. float64 numbers and maps with string keys, by
+// encoding it as JSON first
+func convert(value interface{}, target interface{}) error {
+	content, err := json.Marshal(jsonCompatible(value))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, target)
+}
+
+// jsonCompatible replaces the maps with arbitrary keys of YAML with maps that can be encoded as JSON
+func jsonCompatible(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			object[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return object
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			object[key] = jsonCompatible(item)
+		}
+		return object
+	case []interface{}:
+		list := make([]interface{}, len(typed))
+		for index, item := range typed {
+			list[index] = jsonCompatible(item)
+		}
+		return list
+	}
+	return value
+}
+
+func entityTypeNames() []string {
+	var names []string
+	for _, entityType := range EntityTypes {
+		names = append(names, entityType.Name)
+	}
+	return names
+}
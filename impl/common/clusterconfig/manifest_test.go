@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/clusterconfig"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manifest", func() {
+
+	It("Parses the entities of each type, a singleton as an object", func() {
+		manifest, _, err := ParseManifest([]byte(`
+diskStores:
+  - name: ds1
+    directories: [{name: /data/ds1}]
+regions:
+  - name: orders
+    type: PARTITION
+    redundantCopies: 1
+pdx:
+  readSerialized: true
+indexes: []
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest["regions"]).To(Equal([]Entity{{"name": "orders", "type": "PARTITION", "redundantCopies": float64(1)}}))
+		Expect(manifest["diskStores"][0]["directories"]).To(Equal([]interface{}{map[string]interface{}{"name": "/data/ds1"}}))
+		Expect(manifest["pdx"]).To(Equal([]Entity{{"readSerialized": true}}))
+		Expect(manifest).To(HaveKeyWithValue("indexes", []Entity{}))
+		Expect(manifest).NotTo(HaveKey("gatewayReceivers"))
+	})
+
+	It("Parses JSON", func() {
+		manifest, _, err := ParseManifest([]byte(`{"regions": [{"name": "orders", "type": "REPLICATE"}]}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest["regions"]).To(HaveLen(1))
+	})
+
+	It("Rejects unknown entity types and entities without identity", func() {
+		_, _, err := ParseManifest([]byte(`functions: []`))
+		Expect(err).To(MatchError(ContainSubstring("unknown entity type functions, use one of: deployments, diskStores")))
+
+		_, _, err = ParseManifest([]byte(`regions: [{type: PARTITION}]`))
+		Expect(err).To(MatchError("regions must identify each region"))
+
+		_, _, err = ParseManifest([]byte(`regions: {name: orders}`))
+		Expect(err).To(MatchError("regions must be a list of objects"))
+
+		_, _, err = ParseManifest([]byte(`groups: g1`))
+		Expect(err).To(MatchError("groups must be a list of group names, 'cluster' for the cluster group"))
+	})
+
+	It("Parses the groups a manifest manages", func() {
+		_, groups, err := ParseManifest([]byte("groups: [g1]\nregions: [{name: customers, group: g1}]"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groups).To(Equal([]string{"g1"}))
+
+		_, groups, err = ParseManifest([]byte("regions: [{name: customers, group: g1}]"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groups).To(BeNil())
+	})
+
+	It("Reads the files of deployments relative to the manifest", func() {
+		dir, err := ioutil.TempDir("", "manifest")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "cluster.yaml")
+		Expect(ioutil.WriteFile(file, []byte("deployments: [{file: lib/app.jar}]"), 0600)).To(Succeed())
+
+		manifest, _, err := ReadManifest(file, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest["deployments"][0]["file"]).To(Equal(filepath.Join(dir, "lib", "app.jar")))
+
+		manifest, _, err = ReadManifest("-", strings.NewReader("regions: [{name: orders}]"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest["regions"]).To(HaveLen(1))
+
+		_, _, err = ReadManifest(filepath.Join(dir, "missing.yaml"), nil)
+		Expect(err).To(MatchError(ContainSubstring("unable to read the manifest")))
+	})
+
+	It("Combines the manifests in a directory", func() {
+		dir, err := ioutil.TempDir("", "manifests")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		Expect(ioutil.WriteFile(filepath.Join(dir, "cluster.yaml"), []byte("regions: [{name: orders}]\npdx: {readSerialized: true}"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "g1.json"), []byte(`{"groups": ["g1"], "regions": [{"name": "customers", "group": "g1"}]}`), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0600)).To(Succeed())
+
+		manifest, groups, err := ReadManifest(dir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest["regions"]).To(HaveLen(2))
+		Expect(groups).To(BeNil(), "the cluster manifest does not name its groups")
+		Expect(manifest["pdx"]).To(HaveLen(1))
+
+		Expect(ioutil.WriteFile(filepath.Join(dir, "g2.yaml"), []byte("pdx: {readSerialized: false}"), 0600)).To(Succeed())
+		_, _, err = ReadManifest(dir, nil)
+		Expect(err).To(MatchError(ContainSubstring("pdx is given in more than one manifest")))
+	})
+
+	It("Splits a manifest by group, the cluster group listing every type", func() {
+		manifest := Manifest{
+			"regions": {{"name": "orders"}, {"name": "customers", "group": "g1"}},
+			"indexes": {},
+		}
+		groups := manifest.ByGroup()
+		Expect(groups).To(HaveLen(2))
+		Expect(groups["cluster"]).To(Equal(Manifest{"regions": {{"name": "orders"}}, "indexes": {}}))
+		Expect(groups["g1"]).To(Equal(Manifest{"regions": {{"name": "customers", "group": "g1"}}}))
+	})
+
+	It("Marshals the entities sorted, in the order of the types and with whole numbers", func() {
+		manifest := Manifest{
+			"regions":    {{"name": "orders", "redundantCopies": float64(1)}, {"name": "customers"}},
+			"diskStores": {{"name": "ds1", "maxOplogSizeInBytes": float64(1073741824)}},
+			"pdx":        {{"readSerialized": true}},
+		}
+		content, err := manifest.Marshal("yaml", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("diskStores:\n- maxOplogSizeInBytes: 1073741824\n  name: ds1\npdx:\n  readSerialized: true\n" +
+			"regions:\n- name: customers\n- name: orders\n  redundantCopies: 1\n"))
+
+		content, err = manifest.Marshal("json", nil)
+		Expect(err).NotTo(HaveOccurred())
+		parsed, _, err := ParseManifest(content)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed["regions"]).To(HaveLen(2))
+	})
+})
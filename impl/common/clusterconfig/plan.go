@@ -0,0 +1,218 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+)
+
+// Actions changing an entity
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// FieldChange is a setting of an entity which differs from the manifest. The path of nested
+// settings is separated by dots
+type FieldChange struct {
+	Path    string
+	Current interface{}
+	Desired interface{}
+}
+
+// Change is what it takes to bring an entity in line with the manifest
+type Change struct {
+	Action string
+	Type   *EntityType
+	Key    string
+	// Entity is the desired configuration, or the current one of an entity to delete
+	Entity Entity
+	Fields []FieldChange
+}
+
+// Plan lists the changes in the order in which they are applied: deletions in the reverse order of
+// the dependencies of the entity types, then creations and updates in the order of the dependencies
+type Plan []Change
+
+// NewPlan compares the configuration of a cluster with a manifest. Only the settings given in the
+// manifest are compared, and only the types of entities listed in the manifest are changed, so
+// entities of a listed type which are not in the manifest are deleted if they are in one of the
+// groups the manifest manages. Nil groups manage all groups
+func NewPlan(desired Manifest, groups []string, current Manifest) Plan {
+	var deletions, changes Plan
+	for _, entityType := range EntityTypes {
+		desiredEntities, listed := desired[entityType.Name]
+		if !listed {
+			continue
+		}
+		currentByKey := byKey(entityType, current[entityType.Name])
+		desiredByKey := byKey(entityType, desiredEntities)
+
+		var typeDeletions Plan
+		for _, key := range sortedKeys(currentByKey) {
+			_, wanted := desiredByKey[key]
+			if !wanted && (groups == nil || common.Contains(groups, group(currentByKey[key]))) {
+				typeDeletions = append(typeDeletions, Change{Action: ActionDelete, Type: entityType, Key: key, Entity: currentByKey[key]})
+			}
+		}
+		deletions = append(typeDeletions, deletions...)
+		for _, key := range sortedKeys(desiredByKey) {
+			entity := desiredByKey[key]
+			existing, exists := currentByKey[key]
+			if !exists {
+				changes = append(changes, Change{Action: ActionCreate, Type: entityType, Key: key, Entity: entity})
+				continue
+			}
+			fields := entityType.compare(entity, existing)
+			if len(fields) > 0 {
+				changes = append(changes, Change{Action: ActionUpdate, Type: entityType, Key: key, Entity: entity, Fields: fields})
+			}
+		}
+	}
+	return append(deletions, changes...)
+}
+
+// compare lists the settings of the desired entity which differ from the current one
+func (t *EntityType) compare(desired Entity, current Entity) []FieldChange {
+	var fields []FieldChange
+	for _, name := range sortedKeys(desired) {
+		if common.Contains(t.keyFields, name) || common.Contains(t.ignored, name) {
+			continue
+		}
+		fields = compareValues(name, desired[name], current[name], fields)
+	}
+	return fields
+}
+
+// compareValues compares nested objects by the settings of the desired one, and other values as a whole
+func compareValues(path string, desired interface{}, current interface{}, fields []FieldChange) []FieldChange {
+	desiredObject, desiredIsObject := desired.(map[string]interface{})
+	currentObject, currentIsObject := current.(map[string]interface{})
+	if desiredIsObject && currentIsObject {
+		for _, name := range sortedKeys(desiredObject) {
+			fields = compareValues(path+"."+name, desiredObject[name], currentObject[name], fields)
+		}
+		return fields
+	}
+	if !reflect.DeepEqual(desired, current) {
+		fields = append(fields, FieldChange{Path: path, Current: current, Desired: desired})
+	}
+	return fields
+}
+
+// Command is the command of the management API which makes the change
+func (c Change) Command() string {
+	switch c.Action {
+	case ActionCreate:
+		return c.Type.Create
+	case ActionUpdate:
+		return c.Type.Update
+	}
+	return c.Type.Delete
+}
+
+// Supported reports whether the cluster provides the command which makes the change
+func (c Change) Supported(commandData *domain.CommandData) bool {
+	_, available := commandData.AvailableEndpoints[c.Command()]
+	return c.Command() != "" && available
+}
+
+// Unsupported lists the changes the cluster does not provide a command for
+func (p Plan) Unsupported(commandData *domain.CommandData) Plan {
+	var unsupported Plan
+	for _, change := range p {
+		if !change.Supported(commandData) {
+			unsupported = append(unsupported, change)
+		}
+	}
+	return unsupported
+}
+
+// Describe presents the plan, each change followed by the settings it creates or updates
+func (p Plan) Describe(commandData *domain.CommandData) string {
+	if len(p) == 0 {
+		return "No changes, the cluster matches the manifest."
+	}
+	var description strings.Builder
+	counts := make(map[string]int)
+	for _, change := range p {
+		counts[change.Action]++
+		symbol := map[string]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}[change.Action]
+		note := ""
+		if !change.Supported(commandData) {
+			symbol = "!"
+			note = ", not supported by the management API"
+		}
+		fmt.Fprintf(&description, "%s %s %s %s%s\n", symbol, change.Action, change.Type.Kind, change.Key, note)
+		switch change.Action {
+		case ActionCreate:
+			for _, name := range sortedKeys(change.Entity) {
+				if !common.Contains(change.Type.keyFields, name) {
+					fmt.Fprintf(&description, "      %s: %s\n", name, valueString(change.Entity[name]))
+				}
+			}
+		case ActionUpdate:
+			for _, field := range change.Fields {
+				fmt.Fprintf(&description, "      %s: %s -> %s\n", field.Path, valueString(field.Current), valueString(field.Desired))
+			}
+		}
+	}
+	fmt.Fprintf(&description, "\nPlan: %d to create, %d to update, %d to delete.", counts[ActionCreate], counts[ActionUpdate], counts[ActionDelete])
+	if unsupported := len(p.Unsupported(commandData)); unsupported > 0 {
+		fmt.Fprintf(&description, " %d not supported by the management API.", unsupported)
+	}
+	return description.String()
+}
+
+// valueString presents a setting, strings as they are and other values as JSON
+func valueString(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		return typed
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(content)
+}
+
+func byKey(entityType *EntityType, entities []Entity) map[string]Entity {
+	keyed := make(map[string]Entity, len(entities))
+	for _, entity := range entities {
+		keyed[entityType.Key(entity)] = entity
+	}
+	return keyed
+}
+
+func sortedKeys(values interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(values).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
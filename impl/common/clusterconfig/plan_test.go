@@ -0,0 +1,133 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package clusterconfig_test
+
+import (
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/clusterconfig"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plan", func() {
+
+	var commandData domain.CommandData
+
+	BeforeEach(func() {
+		commandData.AvailableEndpoints = map[string]domain.RestEndPoint{
+			"create region":       {CommandName: "create region"},
+			"delete region":       {CommandName: "delete region"},
+			"create disk-store":   {CommandName: "create disk-store"},
+			"create index":        {CommandName: "create index"},
+			"delete region index": {CommandName: "delete region index"},
+			"update pdx":          {CommandName: "update pdx"},
+		}
+	})
+
+	It("Creates, updates and deletes entities in the order of their dependencies", func() {
+		desired := Manifest{
+			"regions": {
+				{"name": "orders", "type": "PARTITION"},
+				{"name": "customers", "type": "REPLICATE", "group": "g1"},
+			},
+			"indexes":    {{"name": "idx", "regionPath": "/orders", "expression": "id"}},
+			"diskStores": {{"name": "ds1"}},
+			"pdx":        {{"readSerialized": true}},
+		}
+		current := Manifest{
+			"regions": {
+				{"name": "customers", "type": "REPLICATE", "group": "g1"},
+				{"name": "old", "type": "REPLICATE"},
+			},
+			"indexes": {{"name": "stale", "regionPath": "/old", "expression": "id"}},
+			"pdx":     {{"readSerialized": false, "ignoreUnreadFields": true}},
+		}
+		plan := NewPlan(desired, nil, current)
+		var steps []string
+		for _, change := range plan {
+			steps = append(steps, change.Action+" "+change.Type.Kind+" "+change.Key)
+		}
+		Expect(steps).To(Equal([]string{
+			"delete index old.stale",
+			"delete region old",
+			"create disk-store ds1",
+			"update pdx pdx",
+			"create region orders",
+			"create index orders.idx",
+		}))
+		Expect(plan[3].Fields).To(Equal([]FieldChange{{Path: "readSerialized", Current: false, Desired: true}}))
+	})
+
+	It("Deletes only the entities of the groups an exported group manifest manages", func() {
+		current := Manifest{
+			"regions": {
+				{"name": "orders", "type": "PARTITION"},
+				{"name": "customers", "type": "REPLICATE", "group": "g1"},
+				{"name": "stale", "type": "REPLICATE", "group": "g1"},
+				{"name": "payments", "type": "PARTITION", "group": "g2"},
+			},
+			"indexes":    {{"name": "idx", "regionPath": "/orders", "expression": "id"}},
+			"diskStores": {{"name": "ds1"}, {"name": "ds2", "group": "g2"}},
+		}
+		exported := Manifest{
+			"regions":    {{"name": "orders", "type": "PARTITION"}, {"name": "customers", "type": "REPLICATE", "group": "g1"}},
+			"indexes":    {{"name": "idx", "regionPath": "/orders", "expression": "id"}},
+			"diskStores": {{"name": "ds1"}, {"name": "ds2", "group": "g2"}},
+		}.ByGroup()
+		content, err := exported["g1"].Marshal("yaml", []string{"g1"})
+		Expect(err).NotTo(HaveOccurred())
+		desired, groups, err := ParseManifest(content)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groups).To(Equal([]string{"g1"}))
+
+		plan := NewPlan(desired, groups, current)
+		Expect(plan).To(HaveLen(1))
+		Expect(plan[0].Action).To(Equal(ActionDelete))
+		Expect(plan[0].Key).To(Equal("stale@g1"))
+
+		content, err = exported["cluster"].Marshal("yaml", []string{"cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		desired, groups, err = ParseManifest(content)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(NewPlan(desired, groups, current)).To(BeEmpty())
+	})
+
+	It("Compares only the settings given in the manifest, including nested ones", func() {
+		desired := Manifest{"regions": {{"name": "orders", "eviction": map[string]interface{}{"type": "ENTRY_COUNT"}}}}
+		current := Manifest{"regions": {{"name": "orders", "type": "PARTITION", "eviction": map[string]interface{}{"type": "HEAP_PERCENTAGE", "action": "LOCAL_DESTROY"}}}}
+		plan := NewPlan(desired, nil, current)
+		Expect(plan).To(HaveLen(1))
+		Expect(plan[0].Fields).To(Equal([]FieldChange{{Path: "eviction.type", Current: "HEAP_PERCENTAGE", Desired: "ENTRY_COUNT"}}))
+	})
+
+	It("Leaves the entity types which are not in the manifest alone", func() {
+		plan := NewPlan(Manifest{"regions": {{"name": "orders"}}}, nil, Manifest{"regions": {{"name": "orders"}}, "diskStores": {{"name": "ds1"}}})
+		Expect(plan).To(BeEmpty())
+		Expect(plan.Describe(&commandData)).To(Equal("No changes, the cluster matches the manifest."))
+	})
+
+	It("Describes the changes with their settings and those the cluster cannot make", func() {
+		desired := Manifest{"regions": {{"name": "orders", "type": "PARTITION", "redundantCopies": float64(1)}, {"name": "customers", "type": "PARTITION"}}}
+		current := Manifest{"regions": {{"name": "customers", "type": "REPLICATE"}}}
+		Expect(NewPlan(desired, nil, current).Describe(&commandData)).To(Equal(
+			"! update region customers, not supported by the management API\n" +
+				"      type: REPLICATE -> PARTITION\n" +
+				"+ create region orders\n" +
+				"      redundantCopies: 1\n" +
+				"      type: PARTITION\n" +
+				"\nPlan: 1 to create, 1 to update, 0 to delete. 1 not supported by the management API."))
+	})
+})
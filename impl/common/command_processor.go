@@ -20,10 +20,12 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/cli/cf/errors"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/format"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . Formatter
@@ -31,7 +33,7 @@ import (
 // Formatter interface provides response and other output formatting
 type Formatter interface {
 	DescribeEndpoint(domain.RestEndPoint, bool) string
-	FormatResponse(string, string, bool) (string, error)
+	FormatResponse(string, string, bool, string) (string, error)
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . RequestBuilder
@@ -39,15 +41,25 @@ type Formatter interface {
 // RequestBuilder is function type generating a request
 type RequestBuilder func(endpoint domain.RestEndPoint, commandData *domain.CommandData) (request *http.Request, err error)
 
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . SpecCache
+
+// SpecCache persists the endpoints discovered for a locator between invocations
+type SpecCache interface {
+	Load(locatorAddress string) (entry domain.SpecCacheEntry, found bool)
+	Store(entry domain.SpecCacheEntry) error
+	TTL() time.Duration
+}
+
 // CommandProcessor struct holds the implementation for the RequestHelper interface
 type commandProcessor struct {
 	processRequest impl.RequestHelper
 	formatter      Formatter
 	buildRequest   RequestBuilder
+	specCache      SpecCache
 }
 
 // NewCommandProcessor provides the constructor for the CommandProcessor
-func NewCommandProcessor(requester impl.RequestHelper, formatter Formatter, requestBuilder RequestBuilder) (impl.CommandProcessor, error) {
+func NewCommandProcessor(requester impl.RequestHelper, formatter Formatter, requestBuilder RequestBuilder, specCache SpecCache) (impl.CommandProcessor, error) {
 	var errorString []string
 	if requester == nil {
 		errorString = append(errorString, "requester")
@@ -58,17 +70,23 @@ func NewCommandProcessor(requester impl.RequestHelper, formatter Formatter, requ
 	if requestBuilder == nil {
 		errorString = append(errorString, "requestBuilder")
 	}
+	if specCache == nil {
+		errorString = append(errorString, "specCache")
+	}
 	if len(errorString) > 0 {
 		return nil, errors.New(strings.Join(errorString, " and ") + " must not be nil")
 	}
-	return &commandProcessor{processRequest: requester, formatter: formatter, buildRequest: requestBuilder}, nil
+	return &commandProcessor{processRequest: requester, formatter: formatter, buildRequest: requestBuilder, specCache: specCache}, nil
 }
 
 // ProcessCommand handles the common steps for executing a command against the Geode cluster
 func (c *commandProcessor) ProcessCommand(commandData *domain.CommandData) (err error) {
-	err = GetEndPoints(commandData, c.processRequest)
-	if err != nil {
-		return
+	// endpoints may already be known, e.g. when processing several commands in a shell session
+	if len(commandData.AvailableEndpoints) == 0 || HasOption(commandData.UserCommand.Parameters, []string{"--refresh-spec"}) {
+		err = c.LoadEndPoints(commandData)
+		if err != nil {
+			return
+		}
 	}
 
 	userCommand := commandData.UserCommand.Command
@@ -85,11 +103,26 @@ func (c *commandProcessor) ProcessCommand(commandData *domain.CommandData) (err
 		return
 	}
 
+	statusEndpoint, idParameter, isOperation := OperationStatusEndpoint(commandData, restEndPoint)
 	if HasOption(commandData.UserCommand.Parameters, []string{"-h", "--help", "-help"}) {
 		fmt.Println(c.formatter.DescribeEndpoint(restEndPoint, true))
+		if isOperation {
+			fmt.Println(format.WaitOptions)
+		}
 		return
 	}
 
+	wait := HasOption(commandData.UserCommand.Parameters, []string{"--wait"})
+	if wait && !isOperation {
+		return errors.New("--wait applies to commands which start an operation, e.g. start rebalance")
+	}
+	if wait {
+		_, _, err = waitOptions(commandData.UserCommand.Parameters)
+		if err != nil {
+			return
+		}
+	}
+
 	err = CheckRequiredParam(restEndPoint, commandData.UserCommand)
 	if err != nil {
 		return
@@ -100,9 +133,21 @@ func (c *commandProcessor) ProcessCommand(commandData *domain.CommandData) (err
 		return
 	}
 
+	// the final status of the operation is shown instead of the response to starting it
+	var operationErr error
+	if wait {
+		urlResponse, operationErr = c.waitForOperation(commandData, urlResponse, statusEndpoint, idParameter)
+		if urlResponse == "" {
+			return operationErr
+		}
+		restEndPoint = statusEndpoint
+	}
+
 	var jqFilter string
 	var userFilter bool
-	if HasOption(commandData.UserCommand.Parameters, []string{"-t", "--table"}) {
+	outputFormat := GetOption(commandData.UserCommand.Parameters, []string{"--output", "-o"})
+	// tabular output formats need rows, so fall back to the endpoint's jq filter when none is given
+	if HasOption(commandData.UserCommand.Parameters, []string{"-t", "--table"}) || format.IsTabular(outputFormat) {
 		jqFilter = GetOption(commandData.UserCommand.Parameters, []string{"--table", "-t"})
 		userFilter = true
 		// if no jqFilter is specified by the user, use the default defined by the rest end point
@@ -115,13 +160,40 @@ func (c *commandProcessor) ProcessCommand(commandData *domain.CommandData) (err
 		}
 	}
 
-	jsonToBePrinted, err := c.formatter.FormatResponse(urlResponse, jqFilter, userFilter)
+	jsonToBePrinted, err := c.formatter.FormatResponse(urlResponse, jqFilter, userFilter, outputFormat)
 	if err != nil {
 		return
 	}
 	fmt.Println(jsonToBePrinted)
 
-	return
+	return operationErr
+}
+
+// LoadEndPoints populates the available endpoints of the cluster, using the spec cache unless
+// the user asked for the specification to be refreshed
+func (c *commandProcessor) LoadEndPoints(commandData *domain.CommandData) error {
+	refresh := HasOption(commandData.UserCommand.Parameters, []string{"--refresh-spec"})
+	return GetCachedEndPoints(commandData, c.processRequest, c.specCache, refresh)
+}
+
+// ExecuteCommand sends the request of a command to the cluster and provides the response without
+// formatting or printing it, e.g. to look up the entities of the cluster
+func (c *commandProcessor) ExecuteCommand(commandData *domain.CommandData) (urlResponse string, err error) {
+	if len(commandData.AvailableEndpoints) == 0 {
+		err = c.LoadEndPoints(commandData)
+		if err != nil {
+			return
+		}
+	}
+	restEndPoint, available := commandData.AvailableEndpoints[commandData.UserCommand.Command]
+	if !available {
+		return "", errors.New("Invalid command: " + commandData.UserCommand.Command)
+	}
+	err = CheckRequiredParam(restEndPoint, commandData.UserCommand)
+	if err != nil {
+		return
+	}
+	return c.executeCommand(commandData)
 }
 
 // CheckRequiredParam checks if required parameters have been provided
@@ -143,7 +215,14 @@ func (c *commandProcessor) executeCommand(commandData *domain.CommandData) (urlR
 	if err != nil {
 		return "", err
 	}
-	urlResponse, _, err = c.processRequest(request)
+	urlResponse, statusCode, err := c.processRequest(request)
+	if _, isClusterError := err.(*ClusterError); isClusterError {
+		return "", err
+	}
+	if err != nil {
+		return "", NewNetworkError(err.Error())
+	}
+	err = CheckResponse(urlResponse, statusCode)
 	return
 }
 
@@ -34,6 +34,7 @@ var _ = Describe("CommandProcessor", func() {
 		requester        *implfakes.FakeRequestHelper
 		formatter        *commonfakes.FakeFormatter
 		requestBuilder   *commonfakes.FakeRequestBuilder
+		specCache        *commonfakes.FakeSpecCache
 		commandProcessor impl.CommandProcessor
 		err              error
 		commandData      domain.CommandData
@@ -44,7 +45,8 @@ var _ = Describe("CommandProcessor", func() {
 		requester = new(implfakes.FakeRequestHelper)
 		formatter = new(commonfakes.FakeFormatter)
 		requestBuilder = new(commonfakes.FakeRequestBuilder)
-		commandProcessor, err = NewCommandProcessor(requester.Spy, formatter, requestBuilder.Spy)
+		specCache = new(commonfakes.FakeSpecCache)
+		commandProcessor, err = NewCommandProcessor(requester.Spy, formatter, requestBuilder.Spy, specCache)
 		Expect(err).NotTo(HaveOccurred())
 		commandData = domain.CommandData{}
 	})
@@ -52,12 +54,13 @@ var _ = Describe("CommandProcessor", func() {
 	Context("NewCommandProcessor", func() {
 		Context("When dependencies are missing", func() {
 			It("Returns an error indicating missing dependencies", func() {
-				cp, err := NewCommandProcessor(nil, nil, nil)
+				cp, err := NewCommandProcessor(nil, nil, nil, nil)
 				Expect(cp).To(BeNil())
 				Expect(err).NotTo(BeNil())
 				Expect(err.Error()).To(ContainSubstring("requester"))
 				Expect(err.Error()).To(ContainSubstring("formatter"))
 				Expect(err.Error()).To(ContainSubstring("requestBuilder"))
+				Expect(err.Error()).To(ContainSubstring("specCache"))
 				Expect(err.Error()).To(ContainSubstring("must not be nil"))
 			})
 		})
@@ -151,6 +154,32 @@ var _ = Describe("CommandProcessor", func() {
 			Expect(requester.CallCount()).To(Equal(2))
 		})
 
+		Context("Endpoints are already known", func() {
+
+			BeforeEach(func() {
+				commandData.AvailableEndpoints = map[string]domain.RestEndPoint{"ping": {CommandName: "ping", HTTPMethod: "get", URL: "/v1/ping"}}
+				commandData.UserCommand.Command = "ping"
+				requester.Returns("pong", 200, nil)
+			})
+
+			It("Does not discover them again", func() {
+				err = commandProcessor.ProcessCommand(&commandData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requester.CallCount()).To(Equal(1))
+				Expect(specCache.LoadCallCount()).To(BeZero())
+			})
+
+			It("Discovers them again when a refresh is requested", func() {
+				commandData.UserCommand.Parameters = map[string]string{"--refresh-spec": ""}
+				requester.ReturnsOnCall(0, "", 404, nil)
+				requester.ReturnsOnCall(1, `{"paths": {"/v1/ping": {"get": {"summary": "ping"}}}}`, 200, nil)
+				err = commandProcessor.ProcessCommand(&commandData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(requester.CallCount()).To(Equal(3))
+				Expect(specCache.StoreCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("Help output", func() {
 
 			BeforeEach(func() {
@@ -255,6 +284,49 @@ var _ = Describe("CommandProcessor", func() {
 					err := commandProcessor.ProcessCommand(&commandData)
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(ContainSubstring("Process request failed"))
+					Expect(ExitCode(err)).To(Equal(ExitCodeNetwork))
+				})
+			})
+
+			Context("When the cluster reports that the entity does not exist", func() {
+
+				BeforeEach(func() {
+					requester.ReturnsOnCall(2, `{"statusCode":"ENTITY_NOT_FOUND","statusMessage":"Region 'regionId' does not exist."}`, 404, nil)
+				})
+
+				It("Returns a not found error with the status message", func() {
+					err := commandProcessor.ProcessCommand(&commandData)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("ENTITY_NOT_FOUND: Region 'regionId' does not exist."))
+					Expect(ExitCode(err)).To(Equal(ExitCodeNotFound))
+					Expect(formatter.FormatResponseCallCount()).To(BeZero())
+				})
+			})
+
+			Context("When the cluster responds with a server error", func() {
+
+				BeforeEach(func() {
+					requester.ReturnsOnCall(2, "<html>Internal Server Error</html>", 500, nil)
+				})
+
+				It("Returns a server error with the status code", func() {
+					err := commandProcessor.ProcessCommand(&commandData)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("Request failed. Status Code: 500"))
+					Expect(ExitCode(err)).To(Equal(ExitCodeServerError))
+				})
+			})
+
+			Context("When the cluster responds successfully", func() {
+
+				BeforeEach(func() {
+					requester.ReturnsOnCall(2, `{"statusCode":"OK"}`, 200, nil)
+				})
+
+				It("Formats the response", func() {
+					err := commandProcessor.ProcessCommand(&commandData)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(formatter.FormatResponseCallCount()).To(Equal(1))
 				})
 			})
 		})
@@ -267,6 +339,7 @@ var _ = Describe("CommandProcessor", func() {
 				fakeResponse = string(JSONBytes)
 				requester.ReturnsOnCall(0, "", 404, nil)
 				requester.ReturnsOnCall(1, fakeResponse, 200, nil)
+				requester.ReturnsOnCall(2, `{"statusCode":"OK"}`, 200, nil)
 
 				commandData.UserCommand.Command = "list members"
 			})
@@ -281,12 +354,43 @@ var _ = Describe("CommandProcessor", func() {
 					err := commandProcessor.ProcessCommand(&commandData)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(formatter.FormatResponseCallCount()).To(Equal(1))
-					_, query, userProvided := formatter.FormatResponseArgsForCall(0)
+					_, query, userProvided, _ := formatter.FormatResponseArgsForCall(0)
 					Expect(userProvided).To(BeTrue())
 					Expect(query).To(Equal("."))
 				})
 			})
 
+			Context("When user selects a tabular output format without a JQ string", func() {
+
+				BeforeEach(func() {
+					commandData.UserCommand.Parameters = map[string]string{"--output": "csv"}
+				})
+
+				It("Calls the formatter with the default JQ string and the output format", func() {
+					err := commandProcessor.ProcessCommand(&commandData)
+					Expect(err).NotTo(HaveOccurred())
+					_, query, userProvided, outputFormat := formatter.FormatResponseArgsForCall(0)
+					Expect(userProvided).To(BeFalse())
+					Expect(query).To(Equal(".result[] | .runtimeInfo[] | {name:.memberName,status:.status}"))
+					Expect(outputFormat).To(Equal("csv"))
+				})
+			})
+
+			Context("When user selects a document output format", func() {
+
+				BeforeEach(func() {
+					commandData.UserCommand.Parameters = map[string]string{"-o": "yaml"}
+				})
+
+				It("Calls the formatter without a JQ string", func() {
+					err := commandProcessor.ProcessCommand(&commandData)
+					Expect(err).NotTo(HaveOccurred())
+					_, query, _, outputFormat := formatter.FormatResponseArgsForCall(0)
+					Expect(query).To(BeEmpty())
+					Expect(outputFormat).To(Equal("yaml"))
+				})
+			})
+
 			Context("When user does not provide JQ string", func() {
 
 				BeforeEach(func() {
@@ -298,7 +402,7 @@ var _ = Describe("CommandProcessor", func() {
 					It("Calls the formatter with the default JQ string", func() {
 						err := commandProcessor.ProcessCommand(&commandData)
 						Expect(err).NotTo(HaveOccurred())
-						_, query, userProvided := formatter.FormatResponseArgsForCall(0)
+						_, query, userProvided, _ := formatter.FormatResponseArgsForCall(0)
 						Expect(userProvided).To(BeFalse())
 						Expect(query).To(Equal(".result[] | .runtimeInfo[] | {name:.memberName,status:.status}"))
 					})
@@ -313,7 +417,7 @@ var _ = Describe("CommandProcessor", func() {
 					It("Calls the formatter with hard-coded '.' JQ string", func() {
 						err := commandProcessor.ProcessCommand(&commandData)
 						Expect(err).NotTo(HaveOccurred())
-						_, query, userProvided := formatter.FormatResponseArgsForCall(0)
+						_, query, userProvided, _ := formatter.FormatResponseArgsForCall(0)
 						Expect(userProvided).To(BeFalse())
 						Expect(query).To(Equal("."))
 					})
@@ -329,6 +433,7 @@ var _ = Describe("CommandProcessor", func() {
 				fakeResponse = string(JSONBytes)
 				requester.ReturnsOnCall(0, "", 404, nil)
 				requester.ReturnsOnCall(1, fakeResponse, 200, nil)
+				requester.ReturnsOnCall(2, `{"statusCode":"OK"}`, 200, nil)
 
 				commandData.UserCommand.Command = "list members"
 			})
@@ -343,7 +448,7 @@ var _ = Describe("CommandProcessor", func() {
 					err := commandProcessor.ProcessCommand(&commandData)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(formatter.FormatResponseCallCount()).To(Equal(1))
-					_, query, userProvided := formatter.FormatResponseArgsForCall(0)
+					_, query, userProvided, _ := formatter.FormatResponseArgsForCall(0)
 					Expect(userProvided).To(BeTrue())
 					Expect(query).To(Equal("."))
 				})
@@ -360,7 +465,7 @@ var _ = Describe("CommandProcessor", func() {
 					It("Calls the formatter with the default JQ string", func() {
 						err := commandProcessor.ProcessCommand(&commandData)
 						Expect(err).NotTo(HaveOccurred())
-						_, query, userProvided := formatter.FormatResponseArgsForCall(0)
+						_, query, userProvided, _ := formatter.FormatResponseArgsForCall(0)
 						Expect(userProvided).To(BeFalse())
 						Expect(query).To(Equal(".result[] | .groups[] | .runtimeInfo[] | {name:.memberName,status:.status}"))
 					})
@@ -375,7 +480,7 @@ var _ = Describe("CommandProcessor", func() {
 					It("Calls the formatter with hard-coded '.' JQ string", func() {
 						err := commandProcessor.ProcessCommand(&commandData)
 						Expect(err).NotTo(HaveOccurred())
-						_, query, userProvided := formatter.FormatResponseArgsForCall(0)
+						_, query, userProvided, _ := formatter.FormatResponseArgsForCall(0)
 						Expect(userProvided).To(BeFalse())
 						Expect(query).To(Equal("."))
 					})
@@ -391,6 +496,7 @@ var _ = Describe("CommandProcessor", func() {
 				fakeResponse = string(JSONBytes)
 				requester.ReturnsOnCall(0, "", 404, nil)
 				requester.ReturnsOnCall(1, fakeResponse, 200, nil)
+				requester.ReturnsOnCall(2, `{"statusCode":"OK"}`, 200, nil)
 
 				commandData.UserCommand.Command = "list members"
 			})
@@ -456,4 +562,43 @@ var _ = Describe("CommandProcessor", func() {
 		})
 	})
 
+	Context("ExecuteCommand", func() {
+
+		BeforeEach(func() {
+			commandData.AvailableEndpoints = map[string]domain.RestEndPoint{
+				"get region": {CommandName: "get region", HTTPMethod: "get", URL: "/v1/regions/{id}",
+					Parameters: []domain.RestAPIParam{{Name: "id", Required: true, In: "path"}}},
+			}
+			commandData.UserCommand = domain.UserCommand{Command: "get region", Parameters: map[string]string{"--id": "r1"}}
+		})
+
+		It("Provides the response without formatting it", func() {
+			requester.Returns(`{"statusCode":"OK"}`, 200, nil)
+			urlResponse, err := commandProcessor.ExecuteCommand(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(urlResponse).To(Equal(`{"statusCode":"OK"}`))
+			Expect(requestBuilder.CallCount()).To(Equal(1))
+			Expect(formatter.FormatResponseCallCount()).To(BeZero())
+		})
+
+		It("Returns an error for an unknown command", func() {
+			commandData.UserCommand.Command = "get regions"
+			_, err := commandProcessor.ExecuteCommand(&commandData)
+			Expect(err).To(MatchError("Invalid command: get regions"))
+			Expect(requester.CallCount()).To(BeZero())
+		})
+
+		It("Returns an error when a required parameter is missing", func() {
+			commandData.UserCommand.Parameters = map[string]string{}
+			_, err := commandProcessor.ExecuteCommand(&commandData)
+			Expect(err).To(MatchError("Required Parameter is missing: id"))
+		})
+
+		It("Returns an error when the cluster reports a failure", func() {
+			requester.Returns(`{"statusCode":"ENTITY_NOT_FOUND","statusMessage":"not found"}`, 404, nil)
+			_, err := commandProcessor.ExecuteCommand(&commandData)
+			Expect(err).To(MatchError("ENTITY_NOT_FOUND: not found"))
+		})
+	})
+
 })
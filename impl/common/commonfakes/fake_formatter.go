@@ -21,12 +21,13 @@ type FakeFormatter struct {
 	describeEndpointReturnsOnCall map[int]struct {
 		result1 string
 	}
-	FormatResponseStub        func(string, string, bool) (string, error)
+	FormatResponseStub        func(string, string, bool, string) (string, error)
 	formatResponseMutex       sync.RWMutex
 	formatResponseArgsForCall []struct {
 		arg1 string
 		arg2 string
 		arg3 bool
+		arg4 string
 	}
 	formatResponseReturns struct {
 		result1 string
@@ -101,18 +102,19 @@ func (fake *FakeFormatter) DescribeEndpointReturnsOnCall(i int, result1 string)
 	}{result1}
 }
 
-func (fake *FakeFormatter) FormatResponse(arg1 string, arg2 string, arg3 bool) (string, error) {
+func (fake *FakeFormatter) FormatResponse(arg1 string, arg2 string, arg3 bool, arg4 string) (string, error) {
 	fake.formatResponseMutex.Lock()
 	ret, specificReturn := fake.formatResponseReturnsOnCall[len(fake.formatResponseArgsForCall)]
 	fake.formatResponseArgsForCall = append(fake.formatResponseArgsForCall, struct {
 		arg1 string
 		arg2 string
 		arg3 bool
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("FormatResponse", []interface{}{arg1, arg2, arg3})
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("FormatResponse", []interface{}{arg1, arg2, arg3, arg4})
 	fake.formatResponseMutex.Unlock()
 	if fake.FormatResponseStub != nil {
-		return fake.FormatResponseStub(arg1, arg2, arg3)
+		return fake.FormatResponseStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -127,17 +129,17 @@ func (fake *FakeFormatter) FormatResponseCallCount() int {
 	return len(fake.formatResponseArgsForCall)
 }
 
-func (fake *FakeFormatter) FormatResponseCalls(stub func(string, string, bool) (string, error)) {
+func (fake *FakeFormatter) FormatResponseCalls(stub func(string, string, bool, string) (string, error)) {
 	fake.formatResponseMutex.Lock()
 	defer fake.formatResponseMutex.Unlock()
 	fake.FormatResponseStub = stub
 }
 
-func (fake *FakeFormatter) FormatResponseArgsForCall(i int) (string, string, bool) {
+func (fake *FakeFormatter) FormatResponseArgsForCall(i int) (string, string, bool, string) {
 	fake.formatResponseMutex.RLock()
 	defer fake.formatResponseMutex.RUnlock()
 	argsForCall := fake.formatResponseArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeFormatter) FormatResponseReturns(result1 string, result2 error) {
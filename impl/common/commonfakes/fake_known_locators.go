@@ -0,0 +1,200 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package commonfakes
+
+import (
+	"sync"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+)
+
+type FakeKnownLocators struct {
+	AcceptStub        func(string, string) (bool, error)
+	acceptMutex       sync.RWMutex
+	acceptArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	acceptReturns struct {
+		result1 bool
+		result2 error
+	}
+	acceptReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	LookupStub        func(string) (string, bool, error)
+	lookupMutex       sync.RWMutex
+	lookupArgsForCall []struct {
+		arg1 string
+	}
+	lookupReturns struct {
+		result1 string
+		result2 bool
+		result3 error
+	}
+	lookupReturnsOnCall map[int]struct {
+		result1 string
+		result2 bool
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeKnownLocators) Accept(arg1 string, arg2 string) (bool, error) {
+	fake.acceptMutex.Lock()
+	ret, specificReturn := fake.acceptReturnsOnCall[len(fake.acceptArgsForCall)]
+	fake.acceptArgsForCall = append(fake.acceptArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("Accept", []interface{}{arg1, arg2})
+	fake.acceptMutex.Unlock()
+	if fake.AcceptStub != nil {
+		return fake.AcceptStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.acceptReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKnownLocators) AcceptCallCount() int {
+	fake.acceptMutex.RLock()
+	defer fake.acceptMutex.RUnlock()
+	return len(fake.acceptArgsForCall)
+}
+
+func (fake *FakeKnownLocators) AcceptCalls(stub func(string, string) (bool, error)) {
+	fake.acceptMutex.Lock()
+	defer fake.acceptMutex.Unlock()
+	fake.AcceptStub = stub
+}
+
+func (fake *FakeKnownLocators) AcceptArgsForCall(i int) (string, string) {
+	fake.acceptMutex.RLock()
+	defer fake.acceptMutex.RUnlock()
+	argsForCall := fake.acceptArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKnownLocators) AcceptReturns(result1 bool, result2 error) {
+	fake.acceptMutex.Lock()
+	defer fake.acceptMutex.Unlock()
+	fake.AcceptStub = nil
+	fake.acceptReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKnownLocators) AcceptReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.acceptMutex.Lock()
+	defer fake.acceptMutex.Unlock()
+	fake.AcceptStub = nil
+	if fake.acceptReturnsOnCall == nil {
+		fake.acceptReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.acceptReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKnownLocators) Lookup(arg1 string) (string, bool, error) {
+	fake.lookupMutex.Lock()
+	ret, specificReturn := fake.lookupReturnsOnCall[len(fake.lookupArgsForCall)]
+	fake.lookupArgsForCall = append(fake.lookupArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("Lookup", []interface{}{arg1})
+	fake.lookupMutex.Unlock()
+	if fake.LookupStub != nil {
+		return fake.LookupStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.lookupReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeKnownLocators) LookupCallCount() int {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	return len(fake.lookupArgsForCall)
+}
+
+func (fake *FakeKnownLocators) LookupCalls(stub func(string) (string, bool, error)) {
+	fake.lookupMutex.Lock()
+	defer fake.lookupMutex.Unlock()
+	fake.LookupStub = stub
+}
+
+func (fake *FakeKnownLocators) LookupArgsForCall(i int) string {
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	argsForCall := fake.lookupArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeKnownLocators) LookupReturns(result1 string, result2 bool, result3 error) {
+	fake.lookupMutex.Lock()
+	defer fake.lookupMutex.Unlock()
+	fake.LookupStub = nil
+	fake.lookupReturns = struct {
+		result1 string
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeKnownLocators) LookupReturnsOnCall(i int, result1 string, result2 bool, result3 error) {
+	fake.lookupMutex.Lock()
+	defer fake.lookupMutex.Unlock()
+	fake.LookupStub = nil
+	if fake.lookupReturnsOnCall == nil {
+		fake.lookupReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 bool
+			result3 error
+		})
+	}
+	fake.lookupReturnsOnCall[i] = struct {
+		result1 string
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeKnownLocators) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.acceptMutex.RLock()
+	defer fake.acceptMutex.RUnlock()
+	fake.lookupMutex.RLock()
+	defer fake.lookupMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeKnownLocators) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ common.KnownLocators = new(FakeKnownLocators)
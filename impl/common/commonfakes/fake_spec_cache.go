@@ -0,0 +1,254 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package commonfakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+)
+
+type FakeSpecCache struct {
+	LoadStub        func(string) (domain.SpecCacheEntry, bool)
+	loadMutex       sync.RWMutex
+	loadArgsForCall []struct {
+		arg1 string
+	}
+	loadReturns struct {
+		result1 domain.SpecCacheEntry
+		result2 bool
+	}
+	loadReturnsOnCall map[int]struct {
+		result1 domain.SpecCacheEntry
+		result2 bool
+	}
+	StoreStub        func(domain.SpecCacheEntry) error
+	storeMutex       sync.RWMutex
+	storeArgsForCall []struct {
+		arg1 domain.SpecCacheEntry
+	}
+	storeReturns struct {
+		result1 error
+	}
+	storeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	TTLStub        func() time.Duration
+	tTLMutex       sync.RWMutex
+	tTLArgsForCall []struct {
+	}
+	tTLReturns struct {
+		result1 time.Duration
+	}
+	tTLReturnsOnCall map[int]struct {
+		result1 time.Duration
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSpecCache) Load(arg1 string) (domain.SpecCacheEntry, bool) {
+	fake.loadMutex.Lock()
+	ret, specificReturn := fake.loadReturnsOnCall[len(fake.loadArgsForCall)]
+	fake.loadArgsForCall = append(fake.loadArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("Load", []interface{}{arg1})
+	fake.loadMutex.Unlock()
+	if fake.LoadStub != nil {
+		return fake.LoadStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.loadReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSpecCache) LoadCallCount() int {
+	fake.loadMutex.RLock()
+	defer fake.loadMutex.RUnlock()
+	return len(fake.loadArgsForCall)
+}
+
+func (fake *FakeSpecCache) LoadCalls(stub func(string) (domain.SpecCacheEntry, bool)) {
+	fake.loadMutex.Lock()
+	defer fake.loadMutex.Unlock()
+	fake.LoadStub = stub
+}
+
+func (fake *FakeSpecCache) LoadArgsForCall(i int) string {
+	fake.loadMutex.RLock()
+	defer fake.loadMutex.RUnlock()
+	argsForCall := fake.loadArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSpecCache) LoadReturns(result1 domain.SpecCacheEntry, result2 bool) {
+	fake.loadMutex.Lock()
+	defer fake.loadMutex.Unlock()
+	fake.LoadStub = nil
+	fake.loadReturns = struct {
+		result1 domain.SpecCacheEntry
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakeSpecCache) LoadReturnsOnCall(i int, result1 domain.SpecCacheEntry, result2 bool) {
+	fake.loadMutex.Lock()
+	defer fake.loadMutex.Unlock()
+	fake.LoadStub = nil
+	if fake.loadReturnsOnCall == nil {
+		fake.loadReturnsOnCall = make(map[int]struct {
+			result1 domain.SpecCacheEntry
+			result2 bool
+		})
+	}
+	fake.loadReturnsOnCall[i] = struct {
+		result1 domain.SpecCacheEntry
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakeSpecCache) Store(arg1 domain.SpecCacheEntry) error {
+	fake.storeMutex.Lock()
+	ret, specificReturn := fake.storeReturnsOnCall[len(fake.storeArgsForCall)]
+	fake.storeArgsForCall = append(fake.storeArgsForCall, struct {
+		arg1 domain.SpecCacheEntry
+	}{arg1})
+	fake.recordInvocation("Store", []interface{}{arg1})
+	fake.storeMutex.Unlock()
+	if fake.StoreStub != nil {
+		return fake.StoreStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.storeReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpecCache) StoreCallCount() int {
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	return len(fake.storeArgsForCall)
+}
+
+func (fake *FakeSpecCache) StoreCalls(stub func(domain.SpecCacheEntry) error) {
+	fake.storeMutex.Lock()
+	defer fake.storeMutex.Unlock()
+	fake.StoreStub = stub
+}
+
+func (fake *FakeSpecCache) StoreArgsForCall(i int) domain.SpecCacheEntry {
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	argsForCall := fake.storeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSpecCache) StoreReturns(result1 error) {
+	fake.storeMutex.Lock()
+	defer fake.storeMutex.Unlock()
+	fake.StoreStub = nil
+	fake.storeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpecCache) StoreReturnsOnCall(i int, result1 error) {
+	fake.storeMutex.Lock()
+	defer fake.storeMutex.Unlock()
+	fake.StoreStub = nil
+	if fake.storeReturnsOnCall == nil {
+		fake.storeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSpecCache) TTL() time.Duration {
+	fake.tTLMutex.Lock()
+	ret, specificReturn := fake.tTLReturnsOnCall[len(fake.tTLArgsForCall)]
+	fake.tTLArgsForCall = append(fake.tTLArgsForCall, struct {
+	}{})
+	fake.recordInvocation("TTL", []interface{}{})
+	fake.tTLMutex.Unlock()
+	if fake.TTLStub != nil {
+		return fake.TTLStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.tTLReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeSpecCache) TTLCallCount() int {
+	fake.tTLMutex.RLock()
+	defer fake.tTLMutex.RUnlock()
+	return len(fake.tTLArgsForCall)
+}
+
+func (fake *FakeSpecCache) TTLCalls(stub func() time.Duration) {
+	fake.tTLMutex.Lock()
+	defer fake.tTLMutex.Unlock()
+	fake.TTLStub = stub
+}
+
+func (fake *FakeSpecCache) TTLReturns(result1 time.Duration) {
+	fake.tTLMutex.Lock()
+	defer fake.tTLMutex.Unlock()
+	fake.TTLStub = nil
+	fake.tTLReturns = struct {
+		result1 time.Duration
+	}{result1}
+}
+
+func (fake *FakeSpecCache) TTLReturnsOnCall(i int, result1 time.Duration) {
+	fake.tTLMutex.Lock()
+	defer fake.tTLMutex.Unlock()
+	fake.TTLStub = nil
+	if fake.tTLReturnsOnCall == nil {
+		fake.tTLReturnsOnCall = make(map[int]struct {
+			result1 time.Duration
+		})
+	}
+	fake.tTLReturnsOnCall[i] = struct {
+		result1 time.Duration
+	}{result1}
+}
+
+func (fake *FakeSpecCache) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.loadMutex.RLock()
+	defer fake.loadMutex.RUnlock()
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	fake.tTLMutex.RLock()
+	defer fake.tTLMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSpecCache) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ common.SpecCache = new(FakeSpecCache)
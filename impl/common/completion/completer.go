@@ -0,0 +1,236 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package completion
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . ValuesCache
+
+// ValuesCache keeps the values listed from the cluster for a short time, so that repeated tab
+// presses do not query the cluster each time
+type ValuesCache interface {
+	Load(key string) (values []string, found bool)
+	Store(key string, values []string) error
+}
+
+// Completer completes command lines, including parameter values listed from the cluster
+type Completer struct {
+	comm        impl.CommandProcessor
+	valuesCache ValuesCache
+}
+
+// identifierKeys are the fields that identify the entities returned by list commands
+var identifierKeys = []string{"id", "name", "memberName", "operationId"}
+
+// NewCompleter provides a constructor for the completion backend
+func NewCompleter(comm impl.CommandProcessor, valuesCache ValuesCache) (*Completer, error) {
+	if comm == nil || valuesCache == nil {
+		return nil, errors.New("command processor and values cache must not be nil")
+	}
+	return &Completer{comm: comm, valuesCache: valuesCache}, nil
+}
+
+// Complete provides the candidates for the last of the words typed after the target. The values of
+// ids and entity names are listed from the cluster when no other candidates are known
+func (c *Completer) Complete(commandData *domain.CommandData, words []string) []string {
+	candidates := Candidates(commandData.AvailableEndpoints, words)
+	if len(candidates) > 0 || len(words) < 2 {
+		return candidates
+	}
+	partial := words[len(words)-1]
+	previous := words[:len(words)-1]
+	if !isOption(previous[len(previous)-1]) || isOption(partial) {
+		return nil
+	}
+	return withPrefix(c.clusterValues(commandData, previous), partial)
+}
+
+// clusterValues runs the list command matching the option before the word being completed
+// and provides the identifiers of the listed entities
+func (c *Completer) clusterValues(commandData *domain.CommandData, previous []string) []string {
+	commandWords, _ := leadingWords(previous)
+	endpoint, available := commandData.AvailableEndpoints[strings.Join(commandWords, " ")]
+	if !available {
+		return nil
+	}
+	given := common.ParseUserCommand(previous[len(commandWords):]).Parameters
+	option := previous[len(previous)-1]
+	listEndpoint, found := ListEndPoint(commandData.AvailableEndpoints, endpoint, strings.TrimPrefix(option, "--"))
+	if !found {
+		return nil
+	}
+
+	listCommand := domain.UserCommand{Command: listEndpoint.CommandName, Parameters: make(map[string]string)}
+	for _, param := range listEndpoint.Parameters {
+		name := "--" + param.Name
+		if value := given[name]; value != "" && name != option {
+			listCommand.Parameters[name] = value
+		}
+	}
+
+	key := valuesKey(commandData, listCommand)
+	if values, found := c.valuesCache.Load(key); found {
+		return values
+	}
+	listData := *commandData
+	listData.UserCommand = listCommand
+	urlResponse, err := c.comm.ExecuteCommand(&listData)
+	if err != nil {
+		return nil
+	}
+	values := Identifiers(urlResponse)
+	_ = c.valuesCache.Store(key, values)
+	return values
+}
+
+// ListEndPoint finds the list command whose entities provide the values of a parameter of an
+// endpoint: the 'id' parameter takes the ids of the entity the endpoint operates on, e.g.
+// 'list regions' for 'get region', and a parameter like 'regionName' takes the names listed
+// for its entity. Commands sharing more words with the endpoint are preferred, so 'indexName'
+// of 'delete region index' is taken from 'list region indexes'
+func ListEndPoint(endpoints map[string]domain.RestEndPoint, endpoint domain.RestEndPoint, paramName string) (domain.RestEndPoint, bool) {
+	if !isListedParam(endpoint, paramName) {
+		return domain.RestEndPoint{}, false
+	}
+	commandWords := strings.Fields(endpoint.CommandName)
+	if len(commandWords) < 2 {
+		return domain.RestEndPoint{}, false
+	}
+	entity := commandWords[len(commandWords)-1]
+	if paramName != "id" {
+		entity = kebabCase(strings.TrimSuffix(paramName, "Name"))
+	}
+	context := commandWords[1 : len(commandWords)-1]
+	for length := len(context); length >= 0; length-- {
+		for _, plural := range []string{entity + "s", entity + "es", entity} {
+			words := append(append([]string{"list"}, context[:length]...), plural)
+			listEndpoint, available := endpoints[strings.Join(words, " ")]
+			if available && strings.EqualFold(listEndpoint.HTTPMethod, "get") {
+				return listEndpoint, true
+			}
+		}
+	}
+	return domain.RestEndPoint{}, false
+}
+
+// isListedParam reports whether a parameter identifies an entity by its id or name
+func isListedParam(endpoint domain.RestEndPoint, paramName string) bool {
+	if paramName != "id" && !(strings.HasSuffix(paramName, "Name") && len(paramName) > len("Name")) {
+		return false
+	}
+	for _, param := range endpoint.Parameters {
+		if param.Name == paramName {
+			return param.In == "path" || param.In == "query"
+		}
+	}
+	return false
+}
+
+// Identifiers extracts the ids or names of the entities in the result of a list command
+func Identifiers(urlResponse string) []string {
+	var response struct {
+		Result []interface{} `json:"result"`
+	}
+	err := json.Unmarshal([]byte(urlResponse), &response)
+	if err != nil {
+		return nil
+	}
+	var values []string
+	for _, item := range response.Result {
+		for _, value := range itemIdentifiers(item) {
+			if !contains(values, value) {
+				values = append(values, value)
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// itemIdentifiers looks for identifiers in the configuration of an entity first and then in its
+// runtime information, which is all that is reported for members
+func itemIdentifiers(item interface{}) (values []string) {
+	object, isObject := item.(map[string]interface{})
+	if !isObject {
+		return nil
+	}
+	if groups, hasGroups := object["groups"].([]interface{}); hasGroups {
+		for _, group := range groups {
+			values = append(values, itemIdentifiers(group)...)
+		}
+		return
+	}
+	if value := identifier(object["configuration"]); value != "" {
+		return []string{value}
+	}
+	if runtimeInfos, hasRuntimeInfo := object["runtimeInfo"].([]interface{}); hasRuntimeInfo {
+		for _, runtimeInfo := range runtimeInfos {
+			if value := identifier(runtimeInfo); value != "" {
+				values = append(values, value)
+			}
+		}
+		return
+	}
+	if value := identifier(object); value != "" {
+		return []string{value}
+	}
+	return nil
+}
+
+func identifier(item interface{}) string {
+	object, isObject := item.(map[string]interface{})
+	if !isObject {
+		return ""
+	}
+	for _, key := range identifierKeys {
+		if value, isString := object[key].(string); isString && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func valuesKey(commandData *domain.CommandData, listCommand domain.UserCommand) string {
+	parts := []string{commandData.ConnnectionData.LocatorAddress, commandData.ConnnectionData.Username, listCommand.Command}
+	for name, value := range listCommand.Parameters {
+		parts = append(parts, name+"="+value)
+	}
+	sort.Strings(parts[3:])
+	return strings.Join(parts, "\n")
+}
+
+// kebabCase converts a parameter name like 'diskStore' to the form used in command names
+func kebabCase(name string) string {
+	var converted strings.Builder
+	for _, character := range name {
+		if unicode.IsUpper(character) {
+			converted.WriteRune('-')
+			character = unicode.ToLower(character)
+		}
+		converted.WriteRune(character)
+	}
+	return converted.String()
+}
@@ -0,0 +1,150 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package completion_test
+
+import (
+	"errors"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/completion"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/completion/completionfakes"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/implfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const regionsResponse = `{"statusCode":"OK","result":[
+	{"groups":[{"configuration":{"name":"orders"},"runtimeInfo":[{"entryCount":5}]}]},
+	{"groups":[{"configuration":{"name":"customers"}},{"configuration":{"name":"customers"}}]}]}`
+
+var _ = Describe("Completer", func() {
+
+	var (
+		comm        *implfakes.FakeCommandProcessor
+		valuesCache *completionfakes.FakeValuesCache
+		completer   *Completer
+		commandData domain.CommandData
+	)
+
+	BeforeEach(func() {
+		comm = new(implfakes.FakeCommandProcessor)
+		valuesCache = new(completionfakes.FakeValuesCache)
+		var err error
+		completer, err = NewCompleter(comm, valuesCache)
+		Expect(err).NotTo(HaveOccurred())
+
+		idParam := domain.RestAPIParam{Name: "id", In: "path"}
+		regionNameParam := domain.RestAPIParam{Name: "regionName", In: "path"}
+		commandData = domain.CommandData{
+			ConnnectionData: domain.ConnectionData{LocatorAddress: "http://locator"},
+			AvailableEndpoints: map[string]domain.RestEndPoint{
+				"list regions":        {CommandName: "list regions", HTTPMethod: "get", Parameters: []domain.RestAPIParam{{Name: "id", In: "query"}, {Name: "group", In: "query"}}},
+				"get region":          {CommandName: "get region", HTTPMethod: "get", Parameters: []domain.RestAPIParam{idParam}},
+				"list members":        {CommandName: "list members", HTTPMethod: "get"},
+				"get member":          {CommandName: "get member", HTTPMethod: "get", Parameters: []domain.RestAPIParam{idParam}},
+				"list disk-stores":    {CommandName: "list disk-stores", HTTPMethod: "get"},
+				"get disk-store":      {CommandName: "get disk-store", HTTPMethod: "get", Parameters: []domain.RestAPIParam{idParam}},
+				"list indexes":        {CommandName: "list indexes", HTTPMethod: "get"},
+				"list region indexes": {CommandName: "list region indexes", HTTPMethod: "get", Parameters: []domain.RestAPIParam{regionNameParam}},
+				"delete region index": {CommandName: "delete region index", HTTPMethod: "delete", Parameters: []domain.RestAPIParam{regionNameParam, {Name: "indexName", In: "path"}}},
+				"create region":       {CommandName: "create region", HTTPMethod: "post", Parameters: []domain.RestAPIParam{{Name: "regionConfig", In: "body"}}},
+			},
+		}
+		comm.ExecuteCommandReturns(regionsResponse, nil)
+	})
+
+	It("Requires its dependencies", func() {
+		_, err := NewCompleter(nil, valuesCache)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Offers static candidates without querying the cluster", func() {
+		Expect(completer.Complete(&commandData, []string{"get", "reg"})).To(Equal([]string{"region"}))
+		Expect(comm.ExecuteCommandCallCount()).To(BeZero())
+	})
+
+	It("Lists the ids for the entity of the command", func() {
+		Expect(completer.Complete(&commandData, []string{"get", "region", "--id", ""})).To(Equal([]string{"customers", "orders"}))
+		Expect(comm.ExecuteCommandCallCount()).To(Equal(1))
+		listData := comm.ExecuteCommandArgsForCall(0)
+		Expect(listData.UserCommand.Command).To(Equal("list regions"))
+		Expect(commandData.UserCommand.Command).To(Equal(""))
+	})
+
+	It("Filters the listed values by the partial word", func() {
+		Expect(completer.Complete(&commandData, []string{"get", "region", "--id", "cu"})).To(Equal([]string{"customers"}))
+	})
+
+	It("Maps hyphenated entities and members to their list commands", func() {
+		completer.Complete(&commandData, []string{"get", "disk-store", "--id", ""})
+		completer.Complete(&commandData, []string{"get", "member", "--id", ""})
+		Expect(comm.ExecuteCommandArgsForCall(0).UserCommand.Command).To(Equal("list disk-stores"))
+		Expect(comm.ExecuteCommandArgsForCall(1).UserCommand.Command).To(Equal("list members"))
+	})
+
+	It("Lists the names of the entity a name parameter refers to", func() {
+		completer.Complete(&commandData, []string{"delete", "region", "index", "--regionName", ""})
+		Expect(comm.ExecuteCommandArgsForCall(0).UserCommand.Command).To(Equal("list regions"))
+	})
+
+	It("Prefers list commands sharing words with the command and passes the given parameters", func() {
+		completer.Complete(&commandData, []string{"delete", "region", "index", "--regionName", "orders", "--indexName", ""})
+		listData := comm.ExecuteCommandArgsForCall(0)
+		Expect(listData.UserCommand.Command).To(Equal("list region indexes"))
+		Expect(listData.UserCommand.Parameters).To(Equal(map[string]string{"--regionName": "orders"}))
+	})
+
+	It("Does not list values for other parameters", func() {
+		Expect(completer.Complete(&commandData, []string{"list", "regions", "--group", ""})).To(BeEmpty())
+		Expect(completer.Complete(&commandData, []string{"create", "region", "--regionConfig", ""})).To(BeEmpty())
+		Expect(comm.ExecuteCommandCallCount()).To(BeZero())
+	})
+
+	It("Uses cached values", func() {
+		valuesCache.LoadReturns([]string{"cached"}, true)
+		Expect(completer.Complete(&commandData, []string{"get", "region", "--id", ""})).To(Equal([]string{"cached"}))
+		Expect(comm.ExecuteCommandCallCount()).To(BeZero())
+	})
+
+	It("Caches the listed values", func() {
+		completer.Complete(&commandData, []string{"get", "region", "--id", ""})
+		Expect(valuesCache.StoreCallCount()).To(Equal(1))
+		key, values := valuesCache.StoreArgsForCall(0)
+		Expect(key).To(ContainSubstring("http://locator"))
+		Expect(values).To(Equal([]string{"customers", "orders"}))
+	})
+
+	It("Offers nothing when the cluster cannot be queried", func() {
+		comm.ExecuteCommandReturns("", errors.New("connection refused"))
+		Expect(completer.Complete(&commandData, []string{"get", "region", "--id", ""})).To(BeEmpty())
+		Expect(valuesCache.StoreCallCount()).To(BeZero())
+	})
+
+	Context("Identifiers", func() {
+		It("Uses the member names from the runtime information", func() {
+			response := `{"result":[{"groups":[{"runtimeInfo":[{"memberName":"server1"},{"memberName":"locator1"}]}]}]}`
+			Expect(Identifiers(response)).To(Equal([]string{"locator1", "server1"}))
+		})
+
+		It("Prefers ids over names", func() {
+			Expect(Identifiers(`{"result":[{"configuration":{"id":"a","name":"b"}}]}`)).To(Equal([]string{"a"}))
+		})
+
+		It("Returns nothing for other responses", func() {
+			Expect(Identifiers("pong")).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,136 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package completion
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/format"
+)
+
+// Candidates provides the possible completions of the last of the words typed after the target.
+// The last word is the one being completed and is empty when a new word is started
+func Candidates(endpoints map[string]domain.RestEndPoint, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	partial := words[len(words)-1]
+	previous := words[:len(words)-1]
+
+	// the value of an option
+	if len(previous) > 0 && isOption(previous[len(previous)-1]) && !isOption(partial) {
+		return withPrefix(optionValues(previous[len(previous)-1]), partial)
+	}
+
+	commandWords, hasOptions := leadingWords(previous)
+	typed := strings.Join(commandWords, " ")
+	var candidates []string
+	if !hasOptions && !isOption(partial) {
+		candidates = nextCommandWords(endpoints, typed, partial)
+	}
+	endpoint, available := endpoints[typed]
+	if !available || (partial != "" && !isOption(partial)) {
+		return candidates
+	}
+	return append(candidates, withPrefix(optionNames(endpoint, previous), partial)...)
+}
+
+// nextCommandWords finds the word following the typed words in all command names that start
+// with the typed words and the partial word
+func nextCommandWords(endpoints map[string]domain.RestEndPoint, typed string, partial string) []string {
+	prefix := partial
+	if typed != "" {
+		typed += " "
+		prefix = typed + partial
+	}
+	var words []string
+	for commandName := range endpoints {
+		if !strings.HasPrefix(commandName, prefix) {
+			continue
+		}
+		remaining := strings.Fields(commandName[len(typed):])
+		if len(remaining) == 0 {
+			continue
+		}
+		word := remaining[0]
+		if !contains(words, word) {
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words)
+	return words
+}
+
+// optionNames lists the options of an endpoint followed by the general options, leaving out
+// those that have already been given
+func optionNames(endpoint domain.RestEndPoint, previous []string) []string {
+	var names []string
+	for _, param := range endpoint.Parameters {
+		names = append(names, "--"+param.Name)
+	}
+	names = append(names, format.GeneralOptionNames...)
+
+	var unused []string
+	for _, name := range names {
+		if !contains(previous, name) {
+			unused = append(unused, name)
+		}
+	}
+	return unused
+}
+
+func optionValues(option string) []string {
+	switch option {
+	case "--output", "-o":
+		return format.OutputFormats()
+	}
+	return nil
+}
+
+// leadingWords collects the words of a command name, which precede its options
+func leadingWords(words []string) (commandWords []string, hasOptions bool) {
+	for _, word := range words {
+		if isOption(word) {
+			return commandWords, true
+		}
+		commandWords = append(commandWords, word)
+	}
+	return commandWords, false
+}
+
+func withPrefix(values []string, prefix string) (matching []string) {
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			matching = append(matching, value)
+		}
+	}
+	return
+}
+
+func isOption(word string) bool {
+	return strings.HasPrefix(word, "-")
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
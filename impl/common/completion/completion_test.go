@@ -0,0 +1,87 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package completion_test
+
+import (
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/completion"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Completion", func() {
+
+	var endpoints map[string]domain.RestEndPoint
+
+	BeforeEach(func() {
+		endpoints = map[string]domain.RestEndPoint{
+			"list regions":        {CommandName: "list regions", Parameters: []domain.RestAPIParam{{Name: "id"}, {Name: "group"}}},
+			"list region indexes": {CommandName: "list region indexes", Parameters: []domain.RestAPIParam{{Name: "regionName"}}},
+			"list rebalances":     {CommandName: "list rebalances"},
+			"create region":       {CommandName: "create region", Parameters: []domain.RestAPIParam{{Name: "regionConfig"}}},
+			"create region index": {CommandName: "create region index", Parameters: []domain.RestAPIParam{{Name: "regionName"}}},
+			"ping":                {CommandName: "ping"},
+		}
+	})
+
+	Context("Completing command names", func() {
+		It("Offers the first word of every command", func() {
+			Expect(Candidates(endpoints, []string{""})).To(Equal([]string{"create", "list", "ping"}))
+			Expect(Candidates(endpoints, nil)).To(Equal([]string{"create", "list", "ping"}))
+		})
+
+		It("Offers the next word of the matching commands", func() {
+			Expect(Candidates(endpoints, []string{"list", "re"})).To(Equal([]string{"rebalances", "region", "regions"}))
+			Expect(Candidates(endpoints, []string{"list", "region", ""})).To(Equal([]string{"indexes"}))
+		})
+
+		It("Offers both longer commands and options once a command is complete", func() {
+			candidates := Candidates(endpoints, []string{"create", "region", ""})
+			Expect(candidates[0]).To(Equal("index"))
+			Expect(candidates).To(ContainElement("--regionConfig"))
+		})
+	})
+
+	Context("Completing options", func() {
+		It("Offers the parameters of the endpoint and the general options", func() {
+			candidates := Candidates(endpoints, []string{"list", "regions", "--"})
+			Expect(candidates).To(ContainElement("--id"))
+			Expect(candidates).To(ContainElement("--group"))
+			Expect(candidates).To(ContainElement("--output"))
+			Expect(candidates).NotTo(ContainElement("-o"))
+		})
+
+		It("Leaves out options that have been given", func() {
+			candidates := Candidates(endpoints, []string{"list", "regions", "--id", "r1", "--"})
+			Expect(candidates).NotTo(ContainElement("--id"))
+			Expect(candidates).To(ContainElement("--group"))
+		})
+
+		It("Offers nothing for an unknown command", func() {
+			Expect(Candidates(endpoints, []string{"list", "nothing", "--"})).To(BeEmpty())
+		})
+	})
+
+	Context("Completing option values", func() {
+		It("Offers the output formats", func() {
+			Expect(Candidates(endpoints, []string{"ping", "--output", "j"})).To(Equal([]string{"json", "jsonl"}))
+		})
+
+		It("Offers nothing for free form values", func() {
+			Expect(Candidates(endpoints, []string{"list", "regions", "--id", ""})).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,336 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package completion
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/format"
+)
+
+// CompleteCommand is the hidden command the completion scripts run to complete parameter values
+// from the cluster
+const CompleteCommand = "__complete"
+
+// Shells lists the shells for which completion scripts can be generated
+var Shells = []string{"bash", "fish", "zsh"}
+
+type scriptData struct {
+	Program        string
+	Function       string
+	Complete       string
+	Commands       []string
+	CommandOptions map[string]string
+	GeneralOptions string
+	OutputFormats  string
+}
+
+// Script generates the completion script for a shell. The general options are always completed,
+// command names and their parameters only when the endpoints of a target are known
+func Script(shell string, program string, endpoints map[string]domain.RestEndPoint) (string, error) {
+	scriptTemplate, available := scriptTemplates[shell]
+	if !available {
+		return "", errors.New("unsupported shell: " + shell + ", use one of: " + strings.Join(Shells, ", "))
+	}
+
+	data := scriptData{
+		Program:        program,
+		Complete:       CompleteCommand,
+		Function:       "_" + strings.NewReplacer("-", "_", ".", "_").Replace(program),
+		CommandOptions: make(map[string]string),
+		GeneralOptions: strings.Join(format.GeneralOptionNames, " "),
+		OutputFormats:  strings.Join(format.OutputFormats(), " "),
+	}
+	for commandName, endpoint := range endpoints {
+		data.Commands = append(data.Commands, commandName)
+		var options []string
+		for _, param := range endpoint.Parameters {
+			options = append(options, "--"+param.Name)
+		}
+		data.CommandOptions[commandName] = strings.Join(options, " ")
+	}
+	sort.Strings(data.Commands)
+
+	var script bytes.Buffer
+	err := scriptTemplate.Execute(&script, data)
+	if err != nil {
+		return "", err
+	}
+	return script.String(), nil
+}
+
+var templateFunctions = template.FuncMap{
+	// quote wraps a value in single quotes for bash, zsh and fish
+	"quote": func(value string) string {
+		return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	},
+	"fishQuote": func(value string) string {
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+	},
+}
+
+var scriptTemplates = map[string]*template.Template{
+	"bash": template.Must(template.New("bash").Funcs(templateFunctions).Parse(bashScript)),
+	"zsh":  template.Must(template.New("zsh").Funcs(templateFunctions).Parse(zshScript)),
+	"fish": template.Must(template.New("fish").Funcs(templateFunctions).Parse(fishScript)),
+}
+
+// the words typed after the target are taken from COMP_LINE, as COMP_WORDS splits URLs at colons
+const bashScript = `# bash completion for {{.Program}}
+# load with: source <({{.Program}} completion bash)
+
+{{.Function}}_commands=(
+{{- range .Commands}}
+  {{quote .}}
+{{- end}}
+)
+
+{{.Function}}_command_options() {
+  case "$1" in
+{{- range $command, $options := .CommandOptions}}
+    {{quote $command}}) echo {{quote $options}} ;;
+{{- end}}
+    *) return 1 ;;
+  esac
+}
+
+{{.Function}}() {
+  local line="${COMP_LINE:0:COMP_POINT}" words cur prev start=2 i typed="" rest command options="" candidates=""
+  read -r -a words <<< "$line"
+  [[ "$line" == *" " ]] && words+=("")
+  cur="${words[${#words[@]}-1]}"
+  prev="${words[${#words[@]}-2]}"
+  # targets are URLs, otherwise the target comes from GEODE_TARGET or the current profile
+  if [[ "${words[1]}" != *://* ]]; then
+    start=1
+  fi
+  # the target is not completed
+  (( ${#words[@]} - 1 < start )) && return
+
+  case "$prev" in
+    --output|-o)
+      COMPREPLY=($(compgen -W {{quote .OutputFormats}} -- "$cur"))
+      return ;;
+    -*)
+      # ids and names are listed from the cluster
+      if [[ "$cur" != -* ]]; then
+        candidates=$({{.Program}} {{.Complete}} "${words[@]:1}" 2>/dev/null)
+        if [[ -n "$candidates" ]]; then
+          COMPREPLY=($(compgen -W "$candidates" -- "$cur"))
+          return
+        fi
+      fi ;;
+  esac
+
+  for (( i=start; i<${#words[@]}-1; i++ )); do
+    if [[ "${words[i]}" == -* ]]; then
+      options=1
+      break
+    fi
+    typed="${typed:+$typed }${words[i]}"
+  done
+
+  if [[ -z "$options" && "$cur" != -* ]]; then
+    for command in "${ {{- .Function}}_commands[@]}"; do
+      if [[ -z "$typed" ]]; then
+        rest="$command"
+      elif [[ "$command" == "$typed "* ]]; then
+        rest="${command#"$typed "}"
+      else
+        continue
+      fi
+      [[ " $candidates " == *" ${rest%% *} "* ]] || candidates="$candidates ${rest%% *}"
+    done
+  fi
+  if [[ -n "$typed" ]] && rest=$({{.Function}}_command_options "$typed"); then
+    candidates="$candidates $rest "{{quote .GeneralOptions}}
+  elif [[ "$cur" == -* ]]; then
+    candidates="$candidates "{{quote .GeneralOptions}}
+  fi
+  COMPREPLY=($(compgen -W "$candidates" -- "$cur"))
+}
+
+complete -F {{.Function}} {{.Program}}
+`
+
+// zsh arrays start at 1: words[1] is the program and words[CURRENT] the word being completed
+const zshScript = `#compdef {{.Program}}
+# zsh completion for {{.Program}}
+# load with: source <({{.Program}} completion zsh)
+
+{{.Function}}_commands=(
+{{- range .Commands}}
+  {{quote .}}
+{{- end}}
+)
+
+{{.Function}}_command_options() {
+  case "$1" in
+{{- range $command, $options := .CommandOptions}}
+    {{quote $command}}) print -r -- {{quote $options}} ;;
+{{- end}}
+    *) return 1 ;;
+  esac
+}
+
+{{.Function}}() {
+  emulate -L zsh
+  local cur="${words[CURRENT]}" prev="${words[CURRENT-1]}" start=3 i typed="" rest command options=""
+  local general={{quote .GeneralOptions}} formats={{quote .OutputFormats}}
+  local -a candidates values
+  # targets are URLs, otherwise the target comes from GEODE_TARGET or the current profile
+  if [[ "${words[2]}" != *://* ]]; then
+    start=2
+  fi
+  # the target is not completed
+  (( CURRENT < start )) && return
+
+  case "$prev" in
+    --output|-o)
+      compadd -- ${=formats}
+      return ;;
+    -*)
+      # ids and names are listed from the cluster
+      if [[ "$cur" != -* ]]; then
+        values=(${(f)"$({{.Program}} {{.Complete}} "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+        if (( ${#values} )); then
+          compadd -- $values
+          return
+        fi
+      fi ;;
+  esac
+
+  for (( i = start; i < CURRENT; i++ )); do
+    if [[ "${words[i]}" == -* ]]; then
+      options=1
+      break
+    fi
+    typed="${typed:+$typed }${words[i]}"
+  done
+
+  if [[ -z "$options" && "$cur" != -* ]]; then
+    for command in "${ {{- .Function}}_commands[@]}"; do
+      if [[ -z "$typed" ]]; then
+        rest="$command"
+      elif [[ "$command" == "$typed "* ]]; then
+        rest="${command#"$typed "}"
+      else
+        continue
+      fi
+      candidates+=("${rest%% *}")
+    done
+  fi
+  if [[ -n "$typed" ]] && rest=$({{.Function}}_command_options "$typed"); then
+    candidates+=(${=rest} ${=general})
+  elif [[ "$cur" == -* ]]; then
+    candidates+=(${=general})
+  fi
+  compadd -- ${(u)candidates}
+}
+
+# run when autoloaded from fpath, registered when sourced
+if [[ "${funcstack[1]}" == {{quote .Function}} ]]; then
+  {{.Function}} "$@"
+else
+  compdef {{.Function}} {{.Program}}
+fi
+`
+
+const fishScript = `# fish completion for {{.Program}}
+# load with: {{.Program}} completion fish | source
+
+set -g {{.Function}}_commands
+{{- range .Commands}} {{fishQuote .}}{{end}}
+
+function {{.Function}}_command_options
+    switch $argv[1]
+{{- range $command, $options := .CommandOptions}}
+        case {{fishQuote $command}}
+            string split -n ' ' -- {{fishQuote $options}}
+{{- end}}
+        case '*'
+            return 1
+    end
+end
+
+function {{.Function}}_candidates
+    set -l words (commandline -opc)
+    set -l start 3
+    # targets are URLs, otherwise the target comes from GEODE_TARGET or the current profile
+    if not string match -q -- '*://*' "$words[2]"
+        set start 2
+    end
+    # the target is not completed
+    if test (count $words) -lt (math $start - 1)
+        return
+    end
+
+    switch $words[-1]
+        case --output -o
+            string split ' ' -- {{fishQuote .OutputFormats}}
+            return
+        case '-*'
+            # ids and names are listed from the cluster
+            set -l current (commandline -ct)
+            if not string match -q -- '-*' $current
+                set -l values ({{.Program}} {{.Complete}} $words[2..-1] $current 2>/dev/null)
+                if test (count $values) -gt 0
+                    string join \n -- $values
+                    return
+                end
+            end
+    end
+
+    set -l typed
+    set -l options 0
+    if test (count $words) -ge $start
+        for word in $words[$start..-1]
+            if string match -q -- '-*' $word
+                set options 1
+                break
+            end
+            set -a typed $word
+        end
+    end
+    set typed (string join ' ' -- $typed)
+
+    if test $options -eq 0
+        for command in ${{.Function}}_commands
+            set -l rest
+            if test -z "$typed"
+                set rest $command
+            else if string match -q -- "$typed *" $command
+                set rest (string sub -s (math (string length -- "$typed") + 2) -- $command)
+            else
+                continue
+            end
+            string split -f1 ' ' -- $rest
+        end
+    end
+    if test -n "$typed"; and {{.Function}}_command_options "$typed"
+        string split ' ' -- {{fishQuote .GeneralOptions}}
+    else if string match -q -- '-*' (commandline -ct)
+        string split ' ' -- {{fishQuote .GeneralOptions}}
+    end
+end
+
+complete -c {{.Program}} -f -a '({{.Function}}_candidates)'
+`
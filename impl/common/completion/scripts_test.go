@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package completion_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/completion"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scripts", func() {
+
+	var endpoints map[string]domain.RestEndPoint
+
+	BeforeEach(func() {
+		endpoints = map[string]domain.RestEndPoint{
+			"list regions":        {CommandName: "list regions", Parameters: []domain.RestAPIParam{{Name: "id"}, {Name: "group"}}},
+			"create region index": {CommandName: "create region index", Parameters: []domain.RestAPIParam{{Name: "regionName"}}},
+		}
+	})
+
+	It("Generates a bash script with the commands and their options", func() {
+		script, err := Script("bash", "gemfire", endpoints)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(script).To(ContainSubstring("_gemfire_commands=(\n  'create region index'\n  'list regions'\n)"))
+		Expect(script).To(ContainSubstring("'list regions') echo '--id --group' ;;"))
+		Expect(script).To(ContainSubstring("--refresh-spec"))
+		Expect(script).To(ContainSubstring(`candidates=$(gemfire __complete "${words[@]:1}" 2>/dev/null)`))
+		Expect(script).To(HaveSuffix("complete -F _gemfire gemfire\n"))
+	})
+
+	It("Generates a native zsh script with 1-based word indexes", func() {
+		script, err := Script("zsh", "gemfire", endpoints)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(script).To(HavePrefix("#compdef gemfire\n"))
+		Expect(script).To(ContainSubstring("_gemfire_commands=(\n  'create region index'\n  'list regions'\n)"))
+		Expect(script).To(ContainSubstring("emulate -L zsh"))
+		Expect(script).To(ContainSubstring(`local cur="${words[CURRENT]}" prev="${words[CURRENT-1]}"`))
+		Expect(script).To(ContainSubstring(`(gemfire __complete "${(@)words[2,CURRENT]}" 2>/dev/null)`))
+		Expect(script).To(ContainSubstring("compadd -- ${(u)candidates}"))
+		Expect(script).To(ContainSubstring("compdef _gemfire gemfire"))
+		Expect(script).NotTo(ContainSubstring("bashcompinit"))
+		Expect(script).NotTo(ContainSubstring("read -r -a"))
+	})
+
+	It("Completes commands and options in zsh", func() {
+		zsh, err := exec.LookPath("zsh")
+		if err != nil {
+			Skip("zsh is not installed")
+		}
+		script, err := Script("zsh", "gemfire", endpoints)
+		Expect(err).NotTo(HaveOccurred())
+		dir, err := ioutil.TempDir("", "completion")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "_gemfire")
+		Expect(ioutil.WriteFile(file, []byte(script), 0600)).To(Succeed())
+
+		complete := func(words ...string) []string {
+			// compadd and compdef are provided by the completion system, which is not loaded here
+			arguments := append([]string{"-f", "-c", `compdef() { }; compadd() { shift; print -rl -- "$@"; }; source "$1"; shift; words=("$@"); CURRENT=$#; _gemfire`,
+				"zsh", file}, words...)
+			output, err := exec.Command(zsh, arguments...).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			return strings.Fields(string(output))
+		}
+		Expect(complete("gemfire", "")).To(ConsistOf("create", "list"))
+		Expect(complete("gemfire", "list", "")).To(ConsistOf("regions"))
+		Expect(complete("gemfire", "https://locator:7070", "create", "")).To(ConsistOf("region"))
+		Expect(complete("gemfire", "list", "regions", "--")).To(ContainElements("--id", "--group", "--refresh-spec"))
+		Expect(complete("gemfire", "list", "regions", "-o", "")).To(ContainElement("json"))
+	})
+
+	It("Generates a fish script", func() {
+		script, err := Script("fish", "gemfire", endpoints)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(script).To(ContainSubstring("set -g _gemfire_commands 'create region index' 'list regions'\n"))
+		Expect(script).To(ContainSubstring("case 'list regions'\n            string split -n ' ' -- '--id --group'"))
+		Expect(script).To(ContainSubstring("(gemfire __complete $words[2..-1] $current 2>/dev/null)"))
+		Expect(script).To(HaveSuffix("complete -c gemfire -f -a '(_gemfire_candidates)'\n"))
+	})
+
+	It("Only completes the general options without a target", func() {
+		script, err := Script("bash", "gemfire", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(script).To(ContainSubstring("_gemfire_commands=(\n)"))
+		Expect(script).To(ContainSubstring("--output"))
+	})
+
+	It("Returns an error for an unsupported shell", func() {
+		_, err := Script("tcsh", "gemfire", endpoints)
+		Expect(err).To(MatchError("unsupported shell: tcsh, use one of: bash, fish, zsh"))
+	})
+})
@@ -0,0 +1,256 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/clusterconfig"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/format"
+	"gopkg.in/yaml.v2"
+)
+
+// unrenderedCommands do not render results in an output format: their '--output' selects the
+// format of a manifest, or they have none
+var unrenderedCommands = []string{clusterconfig.CommandExport, clusterconfig.CommandPlan, clusterconfig.CommandApply,
+	clusterconfig.CommandDiff, clusterconfig.CommandCopy}
+
+// Keys lists the settings of a profile in the order they are presented
+var Keys = []string{"target", "auth", "username", "credential-helper", "token-file", "token-url", "client-id", "ca-cert", "skip-ssl-validation", "client-cert", "client-key", "output", "group"}
+
+// Profile holds the settings used to connect to one cluster. Passwords are never stored, a
+// credential helper can provide them instead
+type Profile struct {
+	Target            string `yaml:"target,omitempty"`
+	Auth              string `yaml:"auth,omitempty"`
+	Username          string `yaml:"username,omitempty"`
+	CredentialHelper  string `yaml:"credential-helper,omitempty"`
+	TokenFile         string `yaml:"token-file,omitempty"`
+	TokenURL          string `yaml:"token-url,omitempty"`
+	ClientID          string `yaml:"client-id,omitempty"`
+	CACert            string `yaml:"ca-cert,omitempty"`
+	SkipSSLValidation bool   `yaml:"skip-ssl-validation,omitempty"`
+	ClientCert        string `yaml:"client-cert,omitempty"`
+	ClientKey         string `yaml:"client-key,omitempty"`
+	Output            string `yaml:"output,omitempty"`
+	Group             string `yaml:"group,omitempty"`
+}
+
+// Config is the content of the configuration file
+type Config struct {
+	CurrentProfile string             `yaml:"current-profile,omitempty"`
+	Profiles       map[string]Profile `yaml:"profiles,omitempty"`
+	path           string
+}
+
+// Path is the configuration file named by the 'GEODE_CONFIG' environment variable, or
+// '~/.gemfire/config.yaml'
+func Path() (string, error) {
+	if path := os.Getenv("GEODE_CONFIG"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".gemfire", "config.yaml"), nil
+}
+
+// Load reads a configuration file. A missing file is an empty configuration
+func Load(path string) (*Config, error) {
+	config := &Config{Profiles: make(map[string]Profile), path: path}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.Unmarshal(content, config)
+	if err != nil {
+		return nil, errors.New("Invalid configuration file " + path + ": " + err.Error())
+	}
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]Profile)
+	}
+	return config, nil
+}
+
+// Save writes the configuration back to the file it was loaded from
+func (c *Config) Save() error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, content, 0600)
+}
+
+// Current provides the profile selected with Use
+func (c *Config) Current() (profile Profile, found bool) {
+	if c.CurrentProfile == "" {
+		return
+	}
+	profile, found = c.Profiles[c.CurrentProfile]
+	return
+}
+
+// Use selects the profile applied to commands that do not name a target
+func (c *Config) Use(name string) error {
+	if _, found := c.Profiles[name]; !found {
+		return errors.New("Unknown profile: " + name)
+	}
+	c.CurrentProfile = name
+	return nil
+}
+
+// Set changes a setting of a profile, creating the profile if needed. An empty value removes the setting
+func (c *Config) Set(name string, key string, value string) error {
+	profile := c.Profiles[name]
+	switch key {
+	case "target":
+		profile.Target = value
+	case "auth":
+		if value != "" && !common.Contains(common.AuthModes, value) {
+			return errors.New("Invalid auth: " + value + ", use one of: " + strings.Join(common.AuthModes, ", "))
+		}
+		profile.Auth = value
+	case "username":
+		profile.Username = value
+	case "credential-helper":
+		profile.CredentialHelper = value
+	case "token-file":
+		profile.TokenFile = value
+	case "token-url":
+		profile.TokenURL = value
+	case "client-id":
+		profile.ClientID = value
+	case "ca-cert":
+		profile.CACert = value
+	case "skip-ssl-validation":
+		skip, err := strconv.ParseBool(value)
+		if err != nil && value != "" {
+			return errors.New("Invalid skip-ssl-validation: " + value + ", use true or false")
+		}
+		profile.SkipSSLValidation = skip
+	case "client-cert":
+		profile.ClientCert = value
+	case "client-key":
+		profile.ClientKey = value
+	case "output":
+		if value != "" && !common.Contains(format.OutputFormats(), value) {
+			return errors.New("Invalid output: " + value + ", use one of: " + strings.Join(format.OutputFormats(), ", "))
+		}
+		profile.Output = value
+	case "group":
+		profile.Group = value
+	default:
+		return errors.New("Unknown setting: " + key + ", use one of: " + strings.Join(Keys, ", "))
+	}
+	c.Profiles[name] = profile
+	return nil
+}
+
+// List describes the profiles, marking the current one with an asterisk
+func (c *Config) List() string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		marker := " "
+		if name == c.CurrentProfile {
+			marker = "*"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", marker, name))
+		for _, setting := range c.Profiles[name].settings() {
+			lines = append(lines, "    "+setting)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (p Profile) settings() (settings []string) {
+	values := map[string]string{
+		"target":            p.Target,
+		"auth":              p.Auth,
+		"username":          p.Username,
+		"credential-helper": p.CredentialHelper,
+		"token-file":        p.TokenFile,
+		"token-url":         p.TokenURL,
+		"client-id":         p.ClientID,
+		"ca-cert":           p.CACert,
+		"client-cert":       p.ClientCert,
+		"client-key":        p.ClientKey,
+		"output":            p.Output,
+		"group":             p.Group,
+	}
+	if p.SkipSSLValidation {
+		values["skip-ssl-validation"] = "true"
+	}
+	for _, key := range Keys {
+		if values[key] != "" {
+			settings = append(settings, key+": "+values[key])
+		}
+	}
+	return
+}
+
+// Apply provides the settings of the profile as defaults for the options of a command. Options
+// given on the command line and their environment variables take precedence, and the output format
+// only applies to the commands rendering results
+func (p Profile) Apply(commandData *domain.CommandData) {
+	parameters := commandData.UserCommand.Parameters
+	setDefault := func(value string, variable string, options ...string) {
+		if value == "" || common.HasOption(parameters, options) || (variable != "" && os.Getenv(variable) != "") {
+			return
+		}
+		parameters[options[0]] = value
+	}
+	setDefault(p.Auth, "", "--auth")
+	setDefault(p.Username, "GEODE_USERNAME", "--user", "-u")
+	setDefault(p.CredentialHelper, "GEODE_CREDENTIAL_HELPER", "--credential-helper")
+	if !common.HasOption(parameters, []string{"--token"}) {
+		setDefault(p.TokenFile, "GEODE_TOKEN", "--token-file")
+	}
+	setDefault(p.TokenURL, "GEODE_TOKEN_URL", "--token-url")
+	setDefault(p.ClientID, "GEODE_CLIENT_ID", "--client-id")
+	setDefault(p.CACert, "GEODE_CA_CERT", "--ca-cert")
+	setDefault(p.ClientCert, "GEODE_CLIENT_CERT", "--client-cert")
+	setDefault(p.ClientKey, "GEODE_CLIENT_KEY", "--client-key")
+	if command := strings.Fields(commandData.UserCommand.Command); len(command) == 0 || !common.Contains(unrenderedCommands, command[0]) {
+		setDefault(p.Output, "", "--output", "-o")
+	}
+	setDefault(p.Group, "", "--group")
+	if p.SkipSSLValidation {
+		parameters["--skip-ssl-validation"] = ""
+	}
+}
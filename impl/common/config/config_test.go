@@ -0,0 +1,176 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+
+	var (
+		dir           string
+		path          string
+		configuration *Config
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "config")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, ".gemfire", "config.yaml")
+		configuration, err = Load(path)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Starts without profiles when there is no file", func() {
+		Expect(configuration.Profiles).To(BeEmpty())
+		_, found := configuration.Current()
+		Expect(found).To(BeFalse())
+	})
+
+	It("Saves and loads profiles and the current profile", func() {
+		Expect(configuration.Set("dev", "target", "https://dev:7070")).To(Succeed())
+		Expect(configuration.Set("dev", "skip-ssl-validation", "true")).To(Succeed())
+		Expect(configuration.Set("prod", "target", "https://prod:7070")).To(Succeed())
+		Expect(configuration.Use("prod")).To(Succeed())
+		Expect(configuration.Save()).To(Succeed())
+
+		loaded, err := Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Profiles).To(HaveLen(2))
+		Expect(loaded.Profiles["dev"].SkipSSLValidation).To(BeTrue())
+		current, found := loaded.Current()
+		Expect(found).To(BeTrue())
+		Expect(current.Target).To(Equal("https://prod:7070"))
+	})
+
+	It("Reports an invalid file", func() {
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte("profiles: ["), 0600)).To(Succeed())
+		_, err := Load(path)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid configuration file"))
+	})
+
+	It("Only uses existing profiles", func() {
+		Expect(configuration.Use("dev")).To(MatchError("Unknown profile: dev"))
+	})
+
+	DescribeTable("Validates settings",
+		func(key string, value string, message string) {
+			err := configuration.Set("dev", key, value)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix(message))
+		},
+		Entry("unknown key", "password", "secret", "Unknown setting: password"),
+		Entry("auth mode", "auth", "kerberos", "Invalid auth: kerberos"),
+		Entry("boolean", "skip-ssl-validation", "maybe", "Invalid skip-ssl-validation: maybe"),
+		Entry("output format", "output", "xml", "Invalid output: xml"),
+	)
+
+	It("Removes a setting given an empty value", func() {
+		Expect(configuration.Set("dev", "group", "group1")).To(Succeed())
+		Expect(configuration.Set("dev", "group", "")).To(Succeed())
+		Expect(configuration.Profiles["dev"].Group).To(BeEmpty())
+	})
+
+	It("Lists the profiles with their settings and marks the current one", func() {
+		Expect(configuration.Set("dev", "target", "https://dev:7070")).To(Succeed())
+		Expect(configuration.Set("dev", "output", "yaml")).To(Succeed())
+		Expect(configuration.Set("prod", "target", "https://prod:7070")).To(Succeed())
+		Expect(configuration.Use("dev")).To(Succeed())
+		Expect(configuration.List()).To(Equal("* dev\n    target: https://dev:7070\n    output: yaml\n  prod\n    target: https://prod:7070"))
+	})
+
+	Context("Apply", func() {
+
+		var (
+			profile     Profile
+			commandData domain.CommandData
+		)
+
+		BeforeEach(func() {
+			profile = Profile{Target: "https://dev:7070", Username: "admin", CACert: "ca.pem", SkipSSLValidation: true, Output: "yaml", Group: "group1"}
+			commandData = domain.CommandData{UserCommand: domain.UserCommand{Command: "list regions", Parameters: map[string]string{}}}
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("GEODE_USERNAME")
+		})
+
+		It("Provides the settings as options", func() {
+			profile.Apply(&commandData)
+			Expect(commandData.UserCommand.Parameters).To(Equal(map[string]string{
+				"--user":                "admin",
+				"--ca-cert":             "ca.pem",
+				"--skip-ssl-validation": "",
+				"--output":              "yaml",
+				"--group":               "group1",
+			}))
+		})
+
+		It("Provides the authentication settings as options", func() {
+			profile = Profile{Auth: "token", TokenFile: "token.txt"}
+			profile.Apply(&commandData)
+			Expect(commandData.UserCommand.Parameters).To(Equal(map[string]string{"--auth": "token", "--token-file": "token.txt"}))
+
+			profile = Profile{CredentialHelper: "vault-credentials"}
+			commandData.UserCommand.Parameters = map[string]string{}
+			profile.Apply(&commandData)
+			Expect(commandData.UserCommand.Parameters).To(Equal(map[string]string{"--credential-helper": "vault-credentials"}))
+
+			commandData.UserCommand.Parameters = map[string]string{"--token": "abc"}
+			profile.Apply(&commandData)
+			Expect(commandData.UserCommand.Parameters).NotTo(HaveKey("--token-file"))
+		})
+
+		It("Provides the login settings as options", func() {
+			profile = Profile{TokenURL: "https://uaa/oauth/token", ClientID: "gemfire"}
+			profile.Apply(&commandData)
+			Expect(commandData.UserCommand.Parameters).To(Equal(map[string]string{"--token-url": "https://uaa/oauth/token", "--client-id": "gemfire"}))
+		})
+
+		It("Does not override options or environment variables", func() {
+			commandData.UserCommand.Parameters["-o"] = "csv"
+			os.Setenv("GEODE_USERNAME", "operator")
+			profile.Apply(&commandData)
+			Expect(commandData.UserCommand.Parameters).NotTo(HaveKey("--output"))
+			Expect(commandData.UserCommand.Parameters).NotTo(HaveKey("--user"))
+		})
+
+		It("Provides the output format only to the commands rendering results", func() {
+			for _, command := range []string{"export-config", "plan", "apply", "diff", "copy region"} {
+				commandData.UserCommand = domain.UserCommand{Command: command, Parameters: map[string]string{}}
+				profile.Apply(&commandData)
+				Expect(commandData.UserCommand.Parameters).NotTo(HaveKey("--output"), command)
+				Expect(commandData.UserCommand.Parameters).To(HaveKeyWithValue("--user", "admin"), command)
+			}
+		})
+	})
+})
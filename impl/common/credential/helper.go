@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package credential
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+)
+
+// Credential is the JSON document printed by a credential helper, either a username and password
+// or a bearer token with an optional expiry
+type Credential struct {
+	Username  string    `json:"username"`
+	Password  string    `json:"password"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Run executes a credential helper command with the shell. The locator address is provided in
+// the 'GEODE_LOCATOR_ADDRESS' environment variable, and the helper may prompt on stderr
+func Run(command string, locatorAddress string) (credential Credential, err error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), "GEODE_LOCATOR_ADDRESS="+locatorAddress)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	err = cmd.Run()
+	if err != nil {
+		return credential, common.NewAuthError("The credential helper failed: " + err.Error())
+	}
+
+	err = json.Unmarshal(output.Bytes(), &credential)
+	if err != nil {
+		return credential, common.NewAuthError("The credential helper printed invalid JSON: " + err.Error())
+	}
+	switch {
+	case credential.Token != "":
+		if !credential.ExpiresAt.IsZero() && time.Now().After(credential.ExpiresAt) {
+			return credential, common.NewAuthError("The credential helper provided a token which expired at " + credential.ExpiresAt.String())
+		}
+	case credential.Password == "":
+		return credential, common.NewAuthError("The credential helper provided neither a password nor a token")
+	}
+	return credential, nil
+}
+
+// Apply runs the credential helper and sets the credentials of the connection it provides. When
+// the helper provides a token, it is run again to renew the token if the locator rejects it
+func Apply(command string, connectionData *domain.ConnectionData) error {
+	credential, err := Run(command, connectionData.LocatorAddress)
+	if err != nil {
+		return err
+	}
+	if connectionData.Username == "" {
+		connectionData.Username = credential.Username
+	}
+	connectionData.Password = credential.Password
+	connectionData.Token = credential.Token
+	if credential.Token != "" {
+		connectionData.TokenRenewer = &helperRenewer{command: command, connectionData: connectionData}
+	}
+	return nil
+}
+
+type helperRenewer struct {
+	command        string
+	connectionData *domain.ConnectionData
+}
+
+// RenewToken implements the domain.TokenRenewer interface
+func (r *helperRenewer) RenewToken() (string, error) {
+	credential, err := Run(r.command, r.connectionData.LocatorAddress)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(credential.Token) == "" {
+		return "", common.NewAuthError("The credential helper did not provide a token")
+	}
+	r.connectionData.Token = credential.Token
+	return credential.Token, nil
+}
@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package credential_test
+
+import (
+	"time"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/credential"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Credential helper", func() {
+
+	Context("Run", func() {
+
+		It("Reads a username and password", func() {
+			credential, err := Run(`echo '{"username":"admin","password":"secret"}'`, "https://locator:7070")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential).To(Equal(Credential{Username: "admin", Password: "secret"}))
+		})
+
+		It("Reads a token with its expiry", func() {
+			expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+			credential, err := Run(`echo '{"token":"abc","expiresAt":"`+expiresAt+`"}'`, "https://locator:7070")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.Token).To(Equal("abc"))
+			Expect(credential.ExpiresAt.UTC().Format(time.RFC3339)).To(Equal(expiresAt))
+		})
+
+		It("Provides the locator address to the helper", func() {
+			credential, err := Run(`echo "{\"password\":\"$GEODE_LOCATOR_ADDRESS\"}"`, "https://locator:7070")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credential.Password).To(Equal("https://locator:7070"))
+		})
+
+		It("Rejects expired tokens", func() {
+			_, err := Run(`echo '{"token":"abc","expiresAt":"2000-01-01T00:00:00Z"}'`, "https://locator:7070")
+			Expect(err).To(MatchError(ContainSubstring("expired")))
+		})
+
+		It("Reports helpers that fail or print no credentials", func() {
+			_, err := Run("exit 1", "https://locator:7070")
+			Expect(err).To(MatchError(ContainSubstring("The credential helper failed")))
+			Expect(common.ExitCode(err)).To(Equal(common.ExitCodeAuthFailure))
+
+			_, err = Run("echo not-json", "https://locator:7070")
+			Expect(err).To(MatchError(ContainSubstring("invalid JSON")))
+
+			_, err = Run(`echo '{"username":"admin"}'`, "https://locator:7070")
+			Expect(err).To(MatchError(ContainSubstring("neither a password nor a token")))
+		})
+	})
+
+	Context("Apply", func() {
+
+		var connectionData domain.ConnectionData
+
+		BeforeEach(func() {
+			connectionData = domain.ConnectionData{LocatorAddress: "https://locator:7070"}
+		})
+
+		It("Sets the username and password, keeping a username given as an option", func() {
+			connectionData.Username = "operator"
+			Expect(Apply(`echo '{"username":"admin","password":"secret"}'`, &connectionData)).To(Succeed())
+			Expect(connectionData.Username).To(Equal("operator"))
+			Expect(connectionData.Password).To(Equal("secret"))
+			Expect(connectionData.TokenRenewer).To(BeNil())
+		})
+
+		It("Sets the token and renews it by running the helper again", func() {
+			Expect(Apply(`echo '{"token":"abc"}'`, &connectionData)).To(Succeed())
+			Expect(connectionData.Token).To(Equal("abc"))
+			Expect(connectionData.TokenRenewer).NotTo(BeNil())
+
+			connectionData.Token = "rejected"
+			token, err := connectionData.TokenRenewer.RenewToken()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("abc"))
+			Expect(connectionData.Token).To(Equal("abc"))
+		})
+	})
+})
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/cli/cf/errors"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
@@ -47,9 +48,40 @@ const (
 
 // GetEndPoints retrieves available endpoint from the Swagger endpoint on the Geode/PCC locator
 func GetEndPoints(commandData *domain.CommandData, processRequest impl.RequestHelper) error {
+	_, err := discoverEndPoints(commandData, processRequest)
+	return err
+}
+
+// GetCachedEndPoints provides the available endpoints from the spec cache when the cached entry
+// is still fresh, revalidates a stale entry against the locator and otherwise falls back to
+// retrieving them from the Swagger endpoint, storing the result for subsequent invocations
+func GetCachedEndPoints(commandData *domain.CommandData, processRequest impl.RequestHelper, specCache SpecCache, refresh bool) error {
+	entry, found := specCache.Load(commandData.ConnnectionData.LocatorAddress)
+	if found && !refresh {
+		if time.Since(entry.FetchedAt) < specCache.TTL() {
+			applySpecCacheEntry(commandData, entry)
+			return nil
+		}
+		revalidated, ok := revalidateEndPoints(commandData, processRequest, entry)
+		if ok {
+			_ = specCache.Store(revalidated)
+			return nil
+		}
+	}
+
+	entry, err := discoverEndPoints(commandData, processRequest)
+	if err != nil {
+		return err
+	}
+	// failing to write the cache only costs the next invocation a round trip
+	_ = specCache.Store(entry)
+	return nil
+}
+
+func discoverEndPoints(commandData *domain.CommandData, processRequest impl.RequestHelper) (entry domain.SpecCacheEntry, err error) {
 	var urlResponse, apiDocURL string
 	var statusCode int
-	var err error
+	var header http.Header
 	var responseMap map[string]interface{}
 	fallbackCodes := "401 403 404 407"
 	apiDocURLs := []string{
@@ -63,46 +95,106 @@ func GetEndPoints(commandData *domain.CommandData, processRequest impl.RequestHe
 		apiDocURL = URL
 		request, err := http.NewRequest("GET", URL, nil)
 		if err != nil {
-			return err
+			return entry, err
 		}
-		urlResponse, statusCode, err = processRequest(request)
+		header = http.Header{}
+		urlResponse, statusCode, err = processRequest(WithResponseHeader(WithConnectionData(request, commandData.ConnnectionData), header))
 		if err != nil {
-			return errors.New("Unable to reach " + URL + ". Error: " + err.Error())
+			return entry, NewNetworkError("Unable to reach " + URL + ". Error: " + err.Error())
 		}
 		if !strings.Contains(fallbackCodes, strconv.Itoa(statusCode)) {
 			if statusCode == 200 {
 				if pos == 0 {
 					err = json.Unmarshal([]byte(urlResponse), &responseMap)
 					if err != nil {
-						return errors.New("Unable to parse response: " + urlResponse + ". Error: " + err.Error())
+						return entry, errors.New("Unable to parse response: " + urlResponse + ". Error: " + err.Error())
 					}
 					latestURL, Ok := responseMap["latest"]
 					if Ok {
 						apiDocURL = format.GetString(latestURL)
 						request, err := http.NewRequest("GET", apiDocURL, nil)
 						if err != nil {
-							return err
+							return entry, err
 						}
-						urlResponse, statusCode, err = processRequest(request)
+						header = http.Header{}
+						urlResponse, statusCode, err = processRequest(WithResponseHeader(WithConnectionData(request, commandData.ConnnectionData), header))
 						if err != nil {
-							return errors.New("Unable to reach " + apiDocURL + ": " + err.Error())
+							return entry, NewNetworkError("Unable to reach " + apiDocURL + ": " + err.Error())
 						}
 					} else {
-						return errors.New("Unable to determine latest API endpoint: " + urlResponse + ".")
+						return entry, errors.New("Unable to determine latest API endpoint: " + urlResponse + ".")
 					}
 				}
 				break
 			}
-			return errors.New("Unable to reach " + URL + ". Status Code: " + strconv.Itoa(statusCode))
+			return entry, NewStatusError(statusCode, "Unable to reach "+URL+". Status Code: "+strconv.Itoa(statusCode))
 		}
 	}
 
 	if statusCode != 200 {
-		return errors.New("Unable to reach " + apiDocURL + ". Status Code: " + strconv.Itoa(statusCode))
+		return entry, NewStatusError(statusCode, "Unable to reach "+apiDocURL+". Status Code: "+strconv.Itoa(statusCode))
 	}
 
+	err = parseEndPoints(commandData, urlResponse)
+	if err != nil {
+		return entry, err
+	}
+	return newSpecCacheEntry(commandData, apiDocURL, header), nil
+}
+
+// revalidateEndPoints asks the locator whether the cached API documentation is still current,
+// reporting false when the endpoints need to be discovered from scratch
+func revalidateEndPoints(commandData *domain.CommandData, processRequest impl.RequestHelper, entry domain.SpecCacheEntry) (domain.SpecCacheEntry, bool) {
+	request, err := http.NewRequest("GET", entry.SpecURL, nil)
+	if err != nil {
+		return entry, false
+	}
+	if entry.ETag != "" {
+		request.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		request.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+	header := http.Header{}
+	urlResponse, statusCode, err := processRequest(WithResponseHeader(WithConnectionData(request, commandData.ConnnectionData), header))
+	if err != nil {
+		return entry, false
+	}
+	switch statusCode {
+	case http.StatusNotModified:
+		entry.FetchedAt = time.Now()
+		applySpecCacheEntry(commandData, entry)
+		return entry, true
+	case http.StatusOK:
+		if parseEndPoints(commandData, urlResponse) != nil {
+			return entry, false
+		}
+		return newSpecCacheEntry(commandData, entry.SpecURL, header), true
+	}
+	return entry, false
+}
+
+func newSpecCacheEntry(commandData *domain.CommandData, specURL string, header http.Header) domain.SpecCacheEntry {
+	return domain.SpecCacheEntry{
+		LocatorAddress:     commandData.ConnnectionData.LocatorAddress,
+		SpecURL:            specURL,
+		ETag:               header.Get("ETag"),
+		LastModified:       header.Get("Last-Modified"),
+		FetchedAt:          time.Now(),
+		UseToken:           commandData.ConnnectionData.UseToken,
+		AvailableEndpoints: commandData.AvailableEndpoints,
+	}
+}
+
+func applySpecCacheEntry(commandData *domain.CommandData, entry domain.SpecCacheEntry) {
+	commandData.ConnnectionData.UseToken = entry.UseToken
+	commandData.AvailableEndpoints = entry.AvailableEndpoints
+}
+
+// parseEndPoints builds the available endpoints from a Swagger or OpenAPI document
+func parseEndPoints(commandData *domain.CommandData, urlResponse string) error {
 	var apiPaths domain.RestAPI
-	err = json.Unmarshal([]byte(urlResponse), &apiPaths)
+	err := json.Unmarshal([]byte(urlResponse), &apiPaths)
 
 	if err != nil {
 		return errors.New("invalid response " + urlResponse + ": " + err.Error())
@@ -16,8 +16,10 @@
 package common_test
 
 import (
-	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
 	"io/ioutil"
+	"time"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
 
 	"code.cloudfoundry.org/cli/cf/errors"
 	. "github.com/onsi/ginkgo"
@@ -25,6 +27,7 @@ import (
 
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
 	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/commonfakes"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/implfakes"
 )
 
@@ -237,4 +240,115 @@ var _ = Describe("Endpoints", func() {
 		})
 	})
 
+	Describe("GetCachedEndPoints", func() {
+
+		var (
+			requester    *implfakes.FakeRequestHelper
+			specCache    *commonfakes.FakeSpecCache
+			commandData  domain.CommandData
+			fakeResponse string
+			cachedEntry  domain.SpecCacheEntry
+		)
+
+		BeforeEach(func() {
+			requester = new(implfakes.FakeRequestHelper)
+			specCache = new(commonfakes.FakeSpecCache)
+			specCache.TTLReturns(time.Hour)
+			commandData = domain.CommandData{}
+			commandData.ConnnectionData.LocatorAddress = "http://localhost:7070"
+			JSONBytes, err := ioutil.ReadFile("../../testdata/api-docs.json")
+			Expect(err).To(BeNil())
+			fakeResponse = string(JSONBytes)
+			cachedEntry = domain.SpecCacheEntry{
+				LocatorAddress:     "http://localhost:7070",
+				SpecURL:            "http://localhost:7070/management/v1/api-docs",
+				ETag:               `"v1"`,
+				FetchedAt:          time.Now(),
+				UseToken:           true,
+				AvailableEndpoints: map[string]domain.RestEndPoint{"ping": {CommandName: "ping"}},
+			}
+		})
+
+		Context("Nothing is cached for the locator", func() {
+			It("Discovers the endpoints and stores them", func() {
+				requester.ReturnsOnCall(0, "", 404, nil)
+				requester.ReturnsOnCall(1, fakeResponse, 200, nil)
+				err := GetCachedEndPoints(&commandData, requester.Spy, specCache, false)
+				Expect(err).To(BeNil())
+				Expect(len(commandData.AvailableEndpoints)).To(Equal(17))
+				Expect(specCache.LoadArgsForCall(0)).To(Equal("http://localhost:7070"))
+				Expect(specCache.StoreCallCount()).To(Equal(1))
+				stored := specCache.StoreArgsForCall(0)
+				Expect(stored.LocatorAddress).To(Equal("http://localhost:7070"))
+				Expect(stored.SpecURL).To(Equal("http://localhost:7070/management/v3/api-docs"))
+				Expect(stored.UseToken).To(BeTrue())
+				Expect(len(stored.AvailableEndpoints)).To(Equal(17))
+			})
+		})
+
+		Context("A fresh entry is cached", func() {
+			BeforeEach(func() {
+				specCache.LoadReturns(cachedEntry, true)
+			})
+
+			It("Uses the cached endpoints without contacting the locator", func() {
+				err := GetCachedEndPoints(&commandData, requester.Spy, specCache, false)
+				Expect(err).To(BeNil())
+				Expect(requester.CallCount()).To(BeZero())
+				Expect(commandData.AvailableEndpoints).To(HaveKey("ping"))
+				Expect(commandData.ConnnectionData.UseToken).To(BeTrue())
+				Expect(specCache.StoreCallCount()).To(BeZero())
+			})
+
+			It("Discovers the endpoints again when a refresh is requested", func() {
+				requester.ReturnsOnCall(0, "", 404, nil)
+				requester.ReturnsOnCall(1, fakeResponse, 200, nil)
+				err := GetCachedEndPoints(&commandData, requester.Spy, specCache, true)
+				Expect(err).To(BeNil())
+				Expect(requester.CallCount()).To(Equal(2))
+				Expect(len(commandData.AvailableEndpoints)).To(Equal(17))
+				Expect(specCache.StoreCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("A stale entry is cached", func() {
+			BeforeEach(func() {
+				cachedEntry.FetchedAt = time.Now().Add(-2 * time.Hour)
+				specCache.LoadReturns(cachedEntry, true)
+			})
+
+			It("Revalidates the entry and keeps it when the spec is not modified", func() {
+				requester.Returns("", 304, nil)
+				err := GetCachedEndPoints(&commandData, requester.Spy, specCache, false)
+				Expect(err).To(BeNil())
+				Expect(requester.CallCount()).To(Equal(1))
+				request := requester.ArgsForCall(0)
+				Expect(request.URL.String()).To(Equal(cachedEntry.SpecURL))
+				Expect(request.Header.Get("If-None-Match")).To(Equal(`"v1"`))
+				Expect(commandData.AvailableEndpoints).To(HaveKey("ping"))
+				Expect(specCache.StoreCallCount()).To(Equal(1))
+				Expect(specCache.StoreArgsForCall(0).FetchedAt).To(BeTemporally(">", cachedEntry.FetchedAt))
+			})
+
+			It("Replaces the entry when the spec has changed", func() {
+				requester.Returns(fakeResponse, 200, nil)
+				err := GetCachedEndPoints(&commandData, requester.Spy, specCache, false)
+				Expect(err).To(BeNil())
+				Expect(requester.CallCount()).To(Equal(1))
+				Expect(len(commandData.AvailableEndpoints)).To(Equal(17))
+				Expect(specCache.StoreArgsForCall(0).SpecURL).To(Equal(cachedEntry.SpecURL))
+			})
+
+			It("Falls back to discovery when the cached spec URL is gone", func() {
+				requester.ReturnsOnCall(0, "", 404, nil)
+				requester.ReturnsOnCall(1, "", 404, nil)
+				requester.ReturnsOnCall(2, fakeResponse, 200, nil)
+				err := GetCachedEndPoints(&commandData, requester.Spy, specCache, false)
+				Expect(err).To(BeNil())
+				Expect(requester.CallCount()).To(Equal(3))
+				Expect(len(commandData.AvailableEndpoints)).To(Equal(17))
+			})
+		})
+	})
+
 })
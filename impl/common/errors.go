@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Process exit codes reported for the different kinds of failures
+const (
+	ExitCodeGeneral     = 1
+	ExitCodeDrift       = 2
+	ExitCodeAuthFailure = 3
+	ExitCodeNotFound    = 4
+	ExitCodeConflict    = 5
+	ExitCodeServerError = 6
+	ExitCodeNetwork     = 7
+	ExitCodeTimeout     = 8
+)
+
+// ClusterError describes a failed exchange with the cluster
+type ClusterError struct {
+	// StatusCode is the HTTP status of the response, 0 when no response was received
+	StatusCode int
+	// ResultCode is the statusCode reported in the ClusterManagementResult, if any
+	ResultCode string
+	Message    string
+	exitCode   int
+}
+
+func (e *ClusterError) Error() string {
+	return e.Message
+}
+
+// ExitCode is the process exit code that reflects the kind of failure
+func (e *ClusterError) ExitCode() int {
+	return e.exitCode
+}
+
+// clusterManagementResult holds the status fields common to all management API responses
+type clusterManagementResult struct {
+	StatusCode    string `json:"statusCode"`
+	StatusMessage string `json:"statusMessage"`
+}
+
+// ClusterManagementResult status codes which indicate that a request succeeded
+var successResultCodes = []string{"", "OK", "ACCEPTED", "IN_PROGRESS"}
+
+var resultCodeExitCodes = map[string]int{
+	"UNAUTHENTICATED":  ExitCodeAuthFailure,
+	"UNAUTHORIZED":     ExitCodeAuthFailure,
+	"ENTITY_NOT_FOUND": ExitCodeNotFound,
+	"ENTITY_EXISTS":    ExitCodeConflict,
+	"ERROR":            ExitCodeServerError,
+}
+
+// NewNetworkError reports a request that did not receive a response
+func NewNetworkError(message string) error {
+	return &ClusterError{Message: message, exitCode: ExitCodeNetwork}
+}
+
+// NewAuthError reports that a request cannot be authenticated
+func NewAuthError(message string) error {
+	return &ClusterError{Message: message, exitCode: ExitCodeAuthFailure}
+}
+
+// NewNotFoundError reports that an entity does not exist
+func NewNotFoundError(message string) error {
+	return &ClusterError{Message: message, exitCode: ExitCodeNotFound}
+}
+
+// NewStatusError reports a response with an unexpected HTTP status
+func NewStatusError(statusCode int, message string) error {
+	return &ClusterError{StatusCode: statusCode, Message: message, exitCode: statusExitCode(statusCode)}
+}
+
+// CheckResponse returns a ClusterError when the HTTP status or the statusCode of the
+// ClusterManagementResult in the response indicate that the request failed
+func CheckResponse(urlResponse string, statusCode int) error {
+	var result clusterManagementResult
+	// not every response is a ClusterManagementResult, e.g. ping
+	_ = json.Unmarshal([]byte(urlResponse), &result)
+
+	success := statusCode >= 200 && statusCode < 300
+	if success && Contains(successResultCodes, result.StatusCode) {
+		return nil
+	}
+
+	clusterError := &ClusterError{StatusCode: statusCode, ResultCode: result.StatusCode}
+	exitCode, known := resultCodeExitCodes[result.StatusCode]
+	if !known {
+		exitCode = statusExitCode(statusCode)
+	}
+	clusterError.exitCode = exitCode
+
+	switch {
+	case result.StatusCode != "" && result.StatusMessage != "":
+		clusterError.Message = result.StatusCode + ": " + result.StatusMessage
+	case result.StatusCode != "":
+		clusterError.Message = "Request failed with status " + result.StatusCode
+	default:
+		clusterError.Message = "Request failed. Status Code: " + strconv.Itoa(statusCode)
+	}
+	return clusterError
+}
+
+// ExitCode provides the process exit code for an error, ExitCodeGeneral unless the
+// error carries a more specific one
+func ExitCode(err error) int {
+	if exitCoder, ok := err.(interface{ ExitCode() int }); ok {
+		return exitCoder.ExitCode()
+	}
+	return ExitCodeGeneral
+}
+
+func statusExitCode(statusCode int) int {
+	switch {
+	case statusCode == 401 || statusCode == 403 || statusCode == 407:
+		return ExitCodeAuthFailure
+	case statusCode == 404:
+		return ExitCodeNotFound
+	case statusCode == 409:
+		return ExitCodeConflict
+	case statusCode >= 500:
+		return ExitCodeServerError
+	}
+	return ExitCodeGeneral
+}
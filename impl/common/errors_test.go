@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common_test
+
+import (
+	"errors"
+
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Errors", func() {
+
+	Describe("CheckResponse", func() {
+
+		DescribeTable("Successful responses",
+			func(urlResponse string, statusCode int) {
+				Expect(CheckResponse(urlResponse, statusCode)).To(Succeed())
+			},
+			Entry("OK result", `{"statusCode":"OK"}`, 200),
+			Entry("accepted operation", `{"statusCode":"ACCEPTED"}`, 202),
+			Entry("operation in progress", `{"statusCode":"IN_PROGRESS"}`, 200),
+			Entry("response without a result", "pong", 200),
+		)
+
+		DescribeTable("Failed responses",
+			func(urlResponse string, statusCode int, message string, exitCode int) {
+				err := CheckResponse(urlResponse, statusCode)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(message))
+				Expect(ExitCode(err)).To(Equal(exitCode))
+				clusterError, ok := err.(*ClusterError)
+				Expect(ok).To(BeTrue())
+				Expect(clusterError.StatusCode).To(Equal(statusCode))
+			},
+			Entry("unauthenticated", `{"statusCode":"UNAUTHENTICATED","statusMessage":"Authentication error."}`, 401,
+				"UNAUTHENTICATED: Authentication error.", ExitCodeAuthFailure),
+			Entry("forbidden without a body", "", 403, "Request failed. Status Code: 403", ExitCodeAuthFailure),
+			Entry("entity exists", `{"statusCode":"ENTITY_EXISTS","statusMessage":"Region 'a' already exists."}`, 409,
+				"ENTITY_EXISTS: Region 'a' already exists.", ExitCodeConflict),
+			Entry("error result with a 200 status", `{"statusCode":"ERROR","statusMessage":"boom"}`, 200,
+				"ERROR: boom", ExitCodeServerError),
+			Entry("illegal argument", `{"statusCode":"ILLEGAL_ARGUMENT","statusMessage":"bad type"}`, 400,
+				"ILLEGAL_ARGUMENT: bad type", ExitCodeGeneral),
+			Entry("gateway error", "", 502, "Request failed. Status Code: 502", ExitCodeServerError),
+		)
+	})
+
+	Describe("ExitCode", func() {
+		It("Returns the general exit code for other errors", func() {
+			Expect(ExitCode(errors.New("something"))).To(Equal(ExitCodeGeneral))
+		})
+
+		It("Returns the network exit code for network errors", func() {
+			Expect(ExitCode(NewNetworkError("unreachable"))).To(Equal(ExitCodeNetwork))
+		})
+	})
+})
@@ -64,12 +64,25 @@ func Fill(columnSize int, value string, filler string) (response string) {
 	return filler + value[:columnSize-5] + "..." + filler
 }
 
-// FormatResponse extracts JSON from a response
-func (formatter *formatter) FormatResponse(urlResponse string, jqFilter string, userFilter bool) (jsonOutput string, err error) {
+// FormatResponse extracts JSON from a response, filters it with the jqFilter when one is given and
+// renders the result in the requested output format. Without an output format the response is shown
+// as indented JSON, or as a table when it has been filtered
+func (formatter *formatter) FormatResponse(urlResponse string, jqFilter string, userFilter bool, outputFormat string) (output string, err error) {
+	if outputFormat == "" {
+		outputFormat = OutputJSON
+		if jqFilter != "" {
+			outputFormat = OutputTable
+		}
+	}
+	render, err := renderer(outputFormat)
+	if err != nil {
+		return "", err
+	}
+
 	if jqFilter == "" {
-		return indent([]byte(urlResponse))
+		return render(urlResponse)
 	}
-	// otherwise use the filter string to generate the list to display in table format
+	// otherwise use the filter string to generate the list to display
 	filteredJSON, err := formatter.filterWithJQ(urlResponse, jqFilter)
 
 	// if using the default jqFilter does not yield any data, then display the unfiltered result
@@ -82,13 +95,15 @@ func (formatter *formatter) FormatResponse(urlResponse string, jqFilter string,
 		return filteredJSON, err
 	}
 
-	// convert the filtered json to tabular format
-	table, err := Tabular(filteredJSON)
+	output, err = render(filteredJSON)
 	if err != nil {
 		return "", err
 	}
 
-	return table + "\n" + "JQFilter: " + jqFilter + "\n", nil
+	if outputFormat == OutputTable {
+		return output + "\n" + "JQFilter: " + jqFilter + "\n", nil
+	}
+	return output, nil
 }
 
 func (formatter *formatter) filterWithJQ(jsonString string, expr string) (string, error) {
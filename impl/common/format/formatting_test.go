@@ -104,21 +104,21 @@ var _ = Describe("Formatting", func() {
 		It("Returns the input as an indented string", func() {
 			inputString := `{"name": "value"}`
 			expectedString := "{\n  \"name\": \"value\"\n}"
-			output, err := formatter.FormatResponse(inputString, "", false)
+			output, err := formatter.FormatResponse(inputString, "", false, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(output).To(Equal(expectedString))
 		})
 
 		It("Returns the input 'as-is'", func() {
 			inputString := "foobar"
-			output, err := formatter.FormatResponse(inputString, "", false)
+			output, err := formatter.FormatResponse(inputString, "", false, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(output).To(Equal(inputString))
 		})
 
 		It("Returns an error when faulty json query string is used", func() {
 			inputString := `[{"name": "value"}]`
-			output, err := formatter.FormatResponse(inputString, ".[], | {name:.name}", true)
+			output, err := formatter.FormatResponse(inputString, ".[], | {name:.name}", true, "")
 			Expect(output).To(BeEmpty())
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("json query failed"))
@@ -126,7 +126,7 @@ var _ = Describe("Formatting", func() {
 
 		It("Returns filtered input with correct userFilter appended", func() {
 			inputString := `[{"name": "value"}]`
-			output, err := formatter.FormatResponse(inputString, ".[] | {name:.name}", true)
+			output, err := formatter.FormatResponse(inputString, ".[] | {name:.name}", true, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(output).To(Equal(" name  \n-------\n value \n\nJQFilter: .[] | {name:.name}\n"))
 		})
@@ -134,7 +134,7 @@ var _ = Describe("Formatting", func() {
 		It("Returns no results with userFilter that yields empty array", func() {
 			inputString := `{"result": []}`
 			filter := `.result[]`
-			output, err := formatter.FormatResponse(inputString, filter, true)
+			output, err := formatter.FormatResponse(inputString, filter, true, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(output).To(Equal("\nJQFilter: " + filter + "\n"))
 		})
@@ -142,14 +142,14 @@ var _ = Describe("Formatting", func() {
 		It("Returns a result with default filter that yields empty array", func() {
 			inputString := `{"result": []}`
 			filter := `.result[]`
-			output, err := formatter.FormatResponse(inputString, filter, false)
+			output, err := formatter.FormatResponse(inputString, filter, false, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(output).To(Equal(" result \n--------\n []     \n\nJQFilter: .\n"))
 		})
 
 		It("Returns all results with default . filter", func() {
 			inputString := `{"name": "value"}`
-			output, err := formatter.FormatResponse(inputString, ".", false)
+			output, err := formatter.FormatResponse(inputString, ".", false, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(output).To(Equal(" name  \n-------\n value \n\nJQFilter: .\n"))
 		})
@@ -157,7 +157,7 @@ var _ = Describe("Formatting", func() {
 		It("Returns list results with filter", func() {
 			inputString := `{"result": [{"name": "value1"}, {"name":"value2"}, {"name":"value3"}, {"name":"value4"}]}`
 			filter := `.result[]`
-			output, err := formatter.FormatResponse(inputString, filter, false)
+			output, err := formatter.FormatResponse(inputString, filter, false, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(output).To(Equal(` name   
 --------
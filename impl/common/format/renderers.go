@@ -0,0 +1,258 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Renderer converts a JSON document, or the JSON array produced by a jq filter, into
+// the text of an output format
+type Renderer func(jsonString string) (string, error)
+
+// Output format names accepted by FormatResponse
+const (
+	OutputJSON     = "json"
+	OutputYAML     = "yaml"
+	OutputCSV      = "csv"
+	OutputTSV      = "tsv"
+	OutputJSONL    = "jsonl"
+	OutputMarkdown = "markdown"
+	OutputTable    = "table"
+)
+
+// renderers is the registry of output formats, keyed by name
+var renderers = map[string]Renderer{
+	OutputJSON:     indentJSON,
+	OutputYAML:     renderYAML,
+	OutputCSV:      renderSeparated(','),
+	OutputTSV:      renderSeparated('\t'),
+	OutputJSONL:    renderJSONLines,
+	OutputMarkdown: renderMarkdown,
+	OutputTable:    Tabular,
+}
+
+// tabularFormats lists the output formats that present rows and columns
+var tabularFormats = []string{OutputCSV, OutputTSV, OutputMarkdown, OutputTable}
+
+// OutputFormats lists the names of all registered output formats
+func OutputFormats() []string {
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render presents a JSON document in an output format, by default as a table
+func Render(jsonString string, outputFormat string) (string, error) {
+	if outputFormat == "" {
+		outputFormat = OutputTable
+	}
+	render, err := renderer(outputFormat)
+	if err != nil {
+		return "", err
+	}
+	return render(jsonString)
+}
+
+func renderer(outputFormat string) (Renderer, error) {
+	render, available := renderers[outputFormat]
+	if !available {
+		return nil, fmt.Errorf("unknown output format: %s, use one of: %s", outputFormat, strings.Join(OutputFormats(), ", "))
+	}
+	return render, nil
+}
+
+// IsTabular reports whether an output format presents rows and columns and therefore
+// benefits from a jq filter selecting them
+func IsTabular(outputFormat string) bool {
+	return contains(tabularFormats, outputFormat)
+}
+
+func indentJSON(jsonString string) (string, error) {
+	return indent([]byte(jsonString))
+}
+
+func renderYAML(jsonString string) (string, error) {
+	value, err := decode(jsonString)
+	if err != nil {
+		return jsonString, nil
+	}
+	yamlBytes, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(yamlBytes), "\n"), nil
+}
+
+func renderJSONLines(jsonString string) (string, error) {
+	value, err := decode(jsonString)
+	if err != nil {
+		return jsonString, nil
+	}
+	items, isArray := value.([]interface{})
+	if !isArray {
+		items = []interface{}{value}
+	}
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		line, err := json.Marshal(item)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, string(line))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func renderSeparated(separator rune) Renderer {
+	return func(jsonString string) (string, error) {
+		columns, rows, err := toRows(jsonString)
+		if err != nil {
+			return "", err
+		}
+		var buffer bytes.Buffer
+		writer := csv.NewWriter(&buffer)
+		writer.Comma = separator
+		err = writer.Write(columns)
+		if err != nil {
+			return "", err
+		}
+		err = writer.WriteAll(rows)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(buffer.String(), "\n"), nil
+	}
+}
+
+func renderMarkdown(jsonString string) (string, error) {
+	columns, rows, err := toRows(jsonString)
+	if err != nil {
+		return "", err
+	}
+	var buffer strings.Builder
+	writeMarkdownRow(&buffer, columns)
+	divider := make([]string, len(columns))
+	for index := range divider {
+		divider[index] = "---"
+	}
+	writeMarkdownRow(&buffer, divider)
+	for _, row := range rows {
+		writeMarkdownRow(&buffer, row)
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+func writeMarkdownRow(buffer *strings.Builder, cells []string) {
+	buffer.WriteString("|")
+	for _, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", "\\|")
+		cell = strings.ReplaceAll(cell, "\n", "<br>")
+		buffer.WriteString(" " + cell + " |")
+	}
+	buffer.WriteString("\n")
+}
+
+// toRows flattens a JSON array of objects, or a single object, into sorted column names and
+// rows of cell values. Scalar array items are presented in a single 'value' column
+func toRows(jsonString string) (columns []string, rows [][]string, err error) {
+	value, err := decode(jsonString)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, isArray := value.([]interface{})
+	if !isArray {
+		items = []interface{}{value}
+	}
+
+	records := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		record, isObject := item.(map[string]interface{})
+		if !isObject {
+			record = map[string]interface{}{"value": item}
+		}
+		for column := range record {
+			if !contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+		records = append(records, record)
+	}
+	sort.Strings(columns)
+
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for index, column := range columns {
+			row[index] = cellValue(record[column])
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows, nil
+}
+
+func cellValue(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		compact, err := json.Marshal(value)
+		if err == nil {
+			return string(compact)
+		}
+	}
+	return GetString(value)
+}
+
+// decode parses JSON keeping integers intact, so that they are not rendered in exponent form
+func decode(jsonString string) (value interface{}, err error) {
+	decoder := json.NewDecoder(strings.NewReader(jsonString))
+	decoder.UseNumber()
+	err = decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeNumbers(value), nil
+}
+
+func normalizeNumbers(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case json.Number:
+		if integer, err := typed.Int64(); err == nil {
+			return integer
+		}
+		if float, err := typed.Float64(); err == nil {
+			return float
+		}
+		return typed.String()
+	case map[string]interface{}:
+		for key, item := range typed {
+			typed[key] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for index, item := range typed {
+			typed[index] = normalizeNumbers(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package format_test
+
+import (
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/filter"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/format"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Renderers", func() {
+
+	var (
+		formatter common.Formatter
+		err       error
+		members   = `{"statusCode": "OK", "result": [{"name": "server1", "status": "online", "port": 40404},` +
+			` {"name": "locator|1", "status": "online", "port": 10334}]}`
+		jqFilter = ".result[]"
+	)
+
+	BeforeEach(func() {
+		formatter, err = New(filter.GOJQFilter)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Renders filtered results as CSV", func() {
+		output, err := formatter.FormatResponse(members, jqFilter, true, "csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("name,port,status\nserver1,40404,online\nlocator|1,10334,online"))
+	})
+
+	It("Renders filtered results as TSV", func() {
+		output, err := formatter.FormatResponse(members, jqFilter, true, "tsv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("name\tport\tstatus\nserver1\t40404\tonline\nlocator|1\t10334\tonline"))
+	})
+
+	It("Renders filtered results as a Markdown table, escaping pipes", func() {
+		output, err := formatter.FormatResponse(members, jqFilter, true, "markdown")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("| name | port | status |\n| --- | --- | --- |\n" +
+			"| server1 | 40404 | online |\n| locator\\|1 | 10334 | online |"))
+	})
+
+	It("Renders filtered results as JSON Lines", func() {
+		output, err := formatter.FormatResponse(members, jqFilter, true, "jsonl")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal(`{"name":"server1","port":40404,"status":"online"}` + "\n" +
+			`{"name":"locator|1","port":10334,"status":"online"}`))
+	})
+
+	It("Renders the unfiltered response as YAML", func() {
+		output, err := formatter.FormatResponse(`{"statusCode": "OK", "result": {"entryCount": 1000000}}`, "", false, "yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("result:\n  entryCount: 1000000\nstatusCode: OK"))
+	})
+
+	It("Renders filtered results as JSON", func() {
+		output, err := formatter.FormatResponse(members, ".result[] | .name", true, "json")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("[\n  \"server1\",\n  \"locator|1\"\n]"))
+	})
+
+	It("Renders the table with the jq filter that was applied", func() {
+		output, err := formatter.FormatResponse(members, ".result[] | {name:.name}", true, "table")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(ContainSubstring("server1"))
+		Expect(output).To(HaveSuffix("JQFilter: .result[] | {name:.name}\n"))
+	})
+
+	It("Presents nested values and scalars in CSV cells as JSON", func() {
+		output, err := formatter.FormatResponse(`[{"id": "a", "groups": ["g1", "g2"]}, "b"]`, ".[]", true, "csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("groups,id,value\n\"[\"\"g1\"\",\"\"g2\"\"]\",a,\n,,b"))
+	})
+
+	It("Returns an error for an unknown output format", func() {
+		_, err := formatter.FormatResponse(members, "", false, "xml")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("unknown output format: xml, use one of: csv, json, jsonl, markdown, table, tsv, yaml"))
+	})
+
+	It("Identifies the tabular output formats", func() {
+		Expect(IsTabular("csv")).To(BeTrue())
+		Expect(IsTabular("markdown")).To(BeTrue())
+		Expect(IsTabular("table")).To(BeTrue())
+		Expect(IsTabular("yaml")).To(BeFalse())
+		Expect(IsTabular("")).To(BeFalse())
+	})
+
+	It("Renders a document without a formatter, by default as a table", func() {
+		output, err := Render(`[{"name": "server1"}]`, "csv")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("name\nserver1"))
+
+		output, err = Render(`[{"name": "server1"}]`, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(ContainSubstring("server1"))
+
+		_, err = Render(`[]`, "xml")
+		Expect(err).To(MatchError(ContainSubstring("unknown output format: xml")))
+	})
+})
@@ -25,5 +25,22 @@ const (
 	InvalidServiceKeyResponse = "The cf service-key response is invalid."
 	GeneralOptions            = "\t\t--user, -u <username>, or a 'GEODE_USERNAME' environment variable sets the username\n" +
 		"\t\t--password, -p <password>, or a 'GEODE_PASSWORD' environment variable sets the password\n" +
-		"\t\t--table, -t [<jqFilter>] outputs in a tabular form"
+		"\t\t--token <token>, --token-file <file_path>, or a 'GEODE_TOKEN' environment variable sets the bearer token\n" +
+		"\t\t--auth <basic|token> selects the authentication, by default token when the locator requires it\n" +
+		"\t\t--table, -t [<jqFilter>] outputs in a tabular form\n" +
+		"\t\t--output, -o <json|yaml|csv|tsv|jsonl|markdown|table> selects the output format, applied after the jqFilter\n" +
+		"\t\t--refresh-spec ignores the cached API specification and fetches it from the locator\n" +
+		"\t\t--ca-cert <pem_file_path>, or a 'GEODE_CA_CERT' environment variable trusts the certificates in the file\n" +
+		"\t\t--skip-ssl-validation disables the verification of the locator's certificate\n" +
+		"\t\t--client-cert <pem_or_pkcs12_file_path>, or a 'GEODE_CLIENT_CERT' environment variable sets the client certificate\n" +
+		"\t\t--client-key <pem_file_path>, or a 'GEODE_CLIENT_KEY' environment variable sets the key of a PEM client certificate\n" +
+		"\t\t--client-cert-passphrase <passphrase>, or a 'GEODE_CLIENT_CERT_PASSPHRASE' environment variable decrypts a PKCS#12 client certificate"
 )
+
+// WaitOptions describes the options of the commands which start an operation
+const WaitOptions = "\t\t--wait waits for the operation to complete and exits with a status reflecting its result\n" +
+	"\t\t--timeout <duration> sets how long to wait, by default 30m\n" +
+	"\t\t--poll-interval <duration> sets how often the status of the operation is checked, by default 2s"
+
+// GeneralOptionNames are the options described in GeneralOptions which apply to every command
+var GeneralOptionNames = []string{"--user", "-u", "--password", "-p", "--token", "--token-file", "--auth", "--table", "-t", "--output", "-o", "--refresh-spec", "--ca-cert", "--skip-ssl-validation", "--client-cert", "--client-key", "--client-cert-passphrase", "--help", "-h"}
@@ -0,0 +1,217 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+)
+
+// OAuth2 grant types supported by Login
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantPassword          = "password"
+	grantRefreshToken      = "refresh_token"
+)
+
+// expiryMargin renews tokens shortly before they expire so that they do not expire in flight
+const expiryMargin = 30 * time.Second
+
+// Grant describes how to obtain a token from the token endpoint of an OAuth2 provider
+type Grant struct {
+	Type         string
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scope        string
+	Username     string
+	Password     string
+}
+
+// Token is an access token together with what is needed to renew it. Secrets are not kept
+type Token struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+	GrantType    string    `json:"grantType"`
+	TokenURL     string    `json:"tokenUrl"`
+	ClientID     string    `json:"clientId"`
+	Scope        string    `json:"scope,omitempty"`
+}
+
+// Expired reports whether the token has to be renewed before it is used
+func (t Token) Expired() bool {
+	return !t.Expiry.IsZero() && time.Now().Add(expiryMargin).After(t.Expiry)
+}
+
+type tokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	RefreshToken     string      `json:"refresh_token"`
+	ExpiresIn        json.Number `json:"expires_in"`
+	Error            string      `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
+
+// Client obtains tokens from token endpoints
+type Client struct {
+	processRequest impl.RequestHelper
+}
+
+// NewClient provides a constructor for the OAuth2 client
+func NewClient(processRequest impl.RequestHelper) (*Client, error) {
+	if processRequest == nil {
+		return nil, errors.New("requester must not be nil")
+	}
+	return &Client{processRequest: processRequest}, nil
+}
+
+// Login requests a token with the client credentials or resource owner password grant. The
+// token endpoint is contacted with the TLS settings of the connection
+func (c *Client) Login(connectionData domain.ConnectionData, grant Grant) (Token, error) {
+	if grant.TokenURL == "" || grant.ClientID == "" {
+		return Token{}, errors.New("A token URL and a client ID are required to log in")
+	}
+	form := url.Values{"grant_type": {grant.Type}}
+	switch grant.Type {
+	case GrantClientCredentials:
+	case GrantPassword:
+		if grant.Username == "" || grant.Password == "" {
+			return Token{}, errors.New("The password grant requires a username and a password")
+		}
+		form.Set("username", grant.Username)
+		form.Set("password", grant.Password)
+	default:
+		return Token{}, errors.New("Invalid grant: " + grant.Type + ", use " + GrantClientCredentials + " or " + GrantPassword)
+	}
+	if grant.Scope != "" {
+		form.Set("scope", grant.Scope)
+	}
+	token := Token{GrantType: grant.Type, TokenURL: grant.TokenURL, ClientID: grant.ClientID, Scope: grant.Scope}
+	return c.requestToken(connectionData, token, grant.ClientSecret, form)
+}
+
+// Refresh renews an expired token with its refresh token, or by requesting a new one when the
+// client credentials grant was used. The client secret is needed unless the client is public
+func (c *Client) Refresh(connectionData domain.ConnectionData, token Token, clientSecret string) (Token, error) {
+	form := url.Values{}
+	switch {
+	case token.RefreshToken != "":
+		form.Set("grant_type", grantRefreshToken)
+		form.Set("refresh_token", token.RefreshToken)
+	case token.GrantType == GrantClientCredentials:
+		form.Set("grant_type", GrantClientCredentials)
+		if token.Scope != "" {
+			form.Set("scope", token.Scope)
+		}
+	default:
+		return Token{}, common.NewAuthError("The login token has expired and cannot be renewed")
+	}
+	renewed, err := c.requestToken(connectionData, token, clientSecret, form)
+	if err != nil {
+		return Token{}, err
+	}
+	// providers do not always issue a new refresh token
+	if renewed.RefreshToken == "" {
+		renewed.RefreshToken = token.RefreshToken
+	}
+	return renewed, nil
+}
+
+func (c *Client) requestToken(connectionData domain.ConnectionData, token Token, clientSecret string, form url.Values) (Token, error) {
+	// public clients identify themselves in the form, confidential ones authenticate
+	if clientSecret == "" {
+		form.Set("client_id", token.ClientID)
+	}
+	request, err := http.NewRequest("POST", token.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if clientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(token.ClientID), url.QueryEscape(clientSecret))
+	}
+
+	urlResponse, statusCode, err := c.processRequest(common.WithConnectionData(request, connectionData))
+	if err != nil {
+		return Token{}, common.NewNetworkError("Unable to reach " + token.TokenURL + ". Error: " + err.Error())
+	}
+	var response tokenResponse
+	_ = json.Unmarshal([]byte(urlResponse), &response)
+	if response.Error != "" {
+		message := "Login failed: " + response.Error
+		if response.ErrorDescription != "" {
+			message += ": " + response.ErrorDescription
+		}
+		return Token{}, common.NewAuthError(message)
+	}
+	if statusCode != http.StatusOK || response.AccessToken == "" {
+		return Token{}, common.NewStatusError(statusCode, "Login failed. Status Code: "+strconv.Itoa(statusCode))
+	}
+
+	token.AccessToken = response.AccessToken
+	token.RefreshToken = response.RefreshToken
+	token.Expiry = time.Time{}
+	if seconds, err := response.ExpiresIn.Int64(); err == nil && seconds > 0 {
+		token.Expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	return token, nil
+}
+
+// TokenStore keeps the tokens obtained by logging in, by locator address
+type TokenStore interface {
+	Load(locatorAddress string) (token Token, found bool)
+	Store(locatorAddress string, token Token) error
+}
+
+// ApplyLoginToken provides the token obtained by logging in to the locator of the connection,
+// renewing it first when it has expired. Tokens given explicitly and basic authentication
+// selected with '--auth' take precedence
+func (c *Client) ApplyLoginToken(connectionData *domain.ConnectionData, tokenStore TokenStore, clientSecret string) error {
+	if connectionData.Token != "" || connectionData.AuthMode == common.AuthBasic {
+		return nil
+	}
+	token, found := tokenStore.Load(connectionData.LocatorAddress)
+	if !found {
+		return nil
+	}
+	if token.Expired() {
+		var err error
+		token, err = c.Refresh(*connectionData, token, clientSecret)
+		if err != nil {
+			if common.ExitCode(err) == common.ExitCodeAuthFailure {
+				return common.NewAuthError(err.Error() + ". Log in again with 'gemfire login'")
+			}
+			return err
+		}
+		err = tokenStore.Store(connectionData.LocatorAddress, token)
+		if err != nil {
+			return err
+		}
+	}
+	connectionData.Token = token.AccessToken
+	connectionData.AuthMode = common.AuthToken
+	return nil
+}
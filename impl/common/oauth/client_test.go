@@ -0,0 +1,231 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package oauth_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/oauth"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+
+	var (
+		server         *httptest.Server
+		requests       []*http.Request
+		response       map[string]interface{}
+		statusCode     int
+		client         *Client
+		connectionData domain.ConnectionData
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		response = map[string]interface{}{"access_token": "access-1", "refresh_token": "refresh-1", "expires_in": 3600}
+		statusCode = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			Expect(request.ParseForm()).To(Succeed())
+			requests = append(requests, request)
+			writer.Header().Set("Content-Type", "application/json")
+			writer.WriteHeader(statusCode)
+			_ = json.NewEncoder(writer).Encode(response)
+		}))
+		var err error
+		client, err = NewClient(common.Exchange)
+		Expect(err).NotTo(HaveOccurred())
+		connectionData = domain.ConnectionData{LocatorAddress: "https://locator:7070"}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Requires a requester", func() {
+		_, err := NewClient(nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("Login", func() {
+
+		It("Obtains a token with the client credentials grant", func() {
+			grant := Grant{Type: GrantClientCredentials, TokenURL: server.URL, ClientID: "gemfire", ClientSecret: "secret", Scope: "gemfire.read"}
+			token, err := client.Login(connectionData, grant)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("access-1"))
+			Expect(token.RefreshToken).To(Equal("refresh-1"))
+			Expect(token.Expiry).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+			Expect(token.Expired()).To(BeFalse())
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal("POST"))
+			Expect(requests[0].PostForm.Get("grant_type")).To(Equal("client_credentials"))
+			Expect(requests[0].PostForm.Get("scope")).To(Equal("gemfire.read"))
+			Expect(requests[0].PostForm).NotTo(HaveKey("client_id"))
+			username, password, ok := requests[0].BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("gemfire"))
+			Expect(password).To(Equal("secret"))
+		})
+
+		It("Obtains a token with the password grant for a public client", func() {
+			grant := Grant{Type: GrantPassword, TokenURL: server.URL, ClientID: "gemfire", Username: "admin", Password: "p@ss"}
+			_, err := client.Login(connectionData, grant)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests[0].PostForm.Get("grant_type")).To(Equal("password"))
+			Expect(requests[0].PostForm.Get("username")).To(Equal("admin"))
+			Expect(requests[0].PostForm.Get("password")).To(Equal("p@ss"))
+			Expect(requests[0].PostForm.Get("client_id")).To(Equal("gemfire"))
+			Expect(requests[0].Header.Get("Authorization")).To(BeEmpty())
+		})
+
+		It("Requires the username and password for the password grant", func() {
+			grant := Grant{Type: GrantPassword, TokenURL: server.URL, ClientID: "gemfire", Username: "admin"}
+			_, err := client.Login(connectionData, grant)
+			Expect(err).To(HaveOccurred())
+			Expect(requests).To(BeEmpty())
+		})
+
+		It("Rejects unknown grants", func() {
+			_, err := client.Login(connectionData, Grant{Type: "implicit", TokenURL: server.URL, ClientID: "gemfire"})
+			Expect(err).To(MatchError(ContainSubstring("Invalid grant: implicit")))
+		})
+
+		It("Reports the errors of the provider as authentication failures", func() {
+			statusCode = http.StatusUnauthorized
+			response = map[string]interface{}{"error": "invalid_client", "error_description": "Bad credentials"}
+			_, err := client.Login(connectionData, Grant{Type: GrantClientCredentials, TokenURL: server.URL, ClientID: "gemfire"})
+			Expect(err).To(MatchError("Login failed: invalid_client: Bad credentials"))
+			Expect(common.ExitCode(err)).To(Equal(common.ExitCodeAuthFailure))
+		})
+
+		It("Reports unexpected responses", func() {
+			statusCode = http.StatusInternalServerError
+			response = map[string]interface{}{}
+			_, err := client.Login(connectionData, Grant{Type: GrantClientCredentials, TokenURL: server.URL, ClientID: "gemfire"})
+			Expect(err).To(HaveOccurred())
+			Expect(common.ExitCode(err)).To(Equal(common.ExitCodeServerError))
+		})
+	})
+
+	Context("Refresh", func() {
+
+		It("Uses the refresh token and keeps it when no new one is issued", func() {
+			delete(response, "refresh_token")
+			token := Token{AccessToken: "old", RefreshToken: "refresh-0", GrantType: GrantPassword, TokenURL: server.URL, ClientID: "gemfire"}
+			renewed, err := client.Refresh(connectionData, token, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(renewed.AccessToken).To(Equal("access-1"))
+			Expect(renewed.RefreshToken).To(Equal("refresh-0"))
+			Expect(requests[0].PostForm.Get("grant_type")).To(Equal("refresh_token"))
+			Expect(requests[0].PostForm.Get("refresh_token")).To(Equal("refresh-0"))
+		})
+
+		It("Requests a new token for the client credentials grant", func() {
+			token := Token{AccessToken: "old", GrantType: GrantClientCredentials, TokenURL: server.URL, ClientID: "gemfire"}
+			_, err := client.Refresh(connectionData, token, "secret")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests[0].PostForm.Get("grant_type")).To(Equal("client_credentials"))
+		})
+
+		It("Cannot renew a password grant token without a refresh token", func() {
+			token := Token{AccessToken: "old", GrantType: GrantPassword, TokenURL: server.URL, ClientID: "gemfire"}
+			_, err := client.Refresh(connectionData, token, "")
+			Expect(common.ExitCode(err)).To(Equal(common.ExitCodeAuthFailure))
+			Expect(requests).To(BeEmpty())
+		})
+	})
+
+	Context("ApplyLoginToken", func() {
+
+		var (
+			dir        string
+			tokenStore TokenStore
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "tokens")
+			Expect(err).NotTo(HaveOccurred())
+			tokenStore, err = NewTokenStore(dir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("Leaves the connection unchanged without a login", func() {
+			Expect(client.ApplyLoginToken(&connectionData, tokenStore, "")).To(Succeed())
+			Expect(connectionData.Token).To(BeEmpty())
+			Expect(connectionData.AuthMode).To(BeEmpty())
+		})
+
+		It("Provides a valid token without contacting the provider", func() {
+			token := Token{AccessToken: "stored", Expiry: time.Now().Add(time.Hour), TokenURL: server.URL, ClientID: "gemfire"}
+			Expect(tokenStore.Store(connectionData.LocatorAddress, token)).To(Succeed())
+			Expect(client.ApplyLoginToken(&connectionData, tokenStore, "")).To(Succeed())
+			Expect(connectionData.Token).To(Equal("stored"))
+			Expect(connectionData.AuthMode).To(Equal(common.AuthToken))
+			Expect(requests).To(BeEmpty())
+		})
+
+		It("Renews and stores an expired token", func() {
+			token := Token{AccessToken: "stored", RefreshToken: "refresh-0", Expiry: time.Now().Add(10 * time.Second), TokenURL: server.URL, ClientID: "gemfire"}
+			Expect(tokenStore.Store(connectionData.LocatorAddress, token)).To(Succeed())
+			Expect(client.ApplyLoginToken(&connectionData, tokenStore, "")).To(Succeed())
+			Expect(connectionData.Token).To(Equal("access-1"))
+			Expect(requests).To(HaveLen(1))
+
+			stored, found := tokenStore.Load(connectionData.LocatorAddress)
+			Expect(found).To(BeTrue())
+			Expect(stored.AccessToken).To(Equal("access-1"))
+			Expect(stored.RefreshToken).To(Equal("refresh-1"))
+		})
+
+		It("Asks to log in again when the token cannot be renewed", func() {
+			statusCode = http.StatusBadRequest
+			response = map[string]interface{}{"error": "invalid_grant"}
+			token := Token{AccessToken: "stored", RefreshToken: "refresh-0", Expiry: time.Now().Add(-time.Hour), TokenURL: server.URL, ClientID: "gemfire"}
+			Expect(tokenStore.Store(connectionData.LocatorAddress, token)).To(Succeed())
+			err := client.ApplyLoginToken(&connectionData, tokenStore, "")
+			Expect(err).To(MatchError(ContainSubstring("gemfire login")))
+			Expect(common.ExitCode(err)).To(Equal(common.ExitCodeAuthFailure))
+		})
+
+		It("Does not replace explicit tokens or basic authentication", func() {
+			token := Token{AccessToken: "stored", TokenURL: server.URL, ClientID: "gemfire"}
+			Expect(tokenStore.Store(connectionData.LocatorAddress, token)).To(Succeed())
+
+			connectionData.Token = "explicit"
+			Expect(client.ApplyLoginToken(&connectionData, tokenStore, "")).To(Succeed())
+			Expect(connectionData.Token).To(Equal("explicit"))
+
+			connectionData.Token = ""
+			connectionData.AuthMode = common.AuthBasic
+			Expect(client.ApplyLoginToken(&connectionData, tokenStore, "")).To(Succeed())
+			Expect(connectionData.Token).To(BeEmpty())
+		})
+	})
+})
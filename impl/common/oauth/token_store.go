@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package oauth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type tokenEntry struct {
+	LocatorAddress string `json:"locatorAddress"`
+	Token          Token  `json:"token"`
+}
+
+type tokenStore struct {
+	dir string
+}
+
+// NewTokenStore provides a constructor for the store of the tokens obtained by logging in to locators
+func NewTokenStore(dir string) (*tokenStore, error) {
+	if dir == "" {
+		return nil, errors.New("the token directory must be specified")
+	}
+	return &tokenStore{dir: dir}, nil
+}
+
+// TokenStoreFromEnvironment constructs the token store in the directory named by the
+// 'GEODE_TOKEN_STORE' environment variable, or '~/.gemfire/tokens'
+func TokenStoreFromEnvironment() (*tokenStore, error) {
+	if dir := os.Getenv("GEODE_TOKEN_STORE"); dir != "" {
+		return NewTokenStore(dir)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewTokenStore(filepath.Join(home, ".gemfire", "tokens"))
+}
+
+// Load reads the token of a locator
+func (ts *tokenStore) Load(locatorAddress string) (token Token, found bool) {
+	content, err := ioutil.ReadFile(ts.path(locatorAddress))
+	if err != nil {
+		return
+	}
+	var entry tokenEntry
+	err = json.Unmarshal(content, &entry)
+	if err != nil || entry.LocatorAddress != locatorAddress {
+		return Token{}, false
+	}
+	return entry.Token, true
+}
+
+// Store writes the token of a locator, readable only by the user
+func (ts *tokenStore) Store(locatorAddress string, token Token) error {
+	content, err := json.Marshal(tokenEntry{LocatorAddress: locatorAddress, Token: token})
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(ts.dir, 0700)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(ts.path(locatorAddress), content, 0600)
+}
+
+func (ts *tokenStore) path(locatorAddress string) string {
+	sum := sha256.Sum256([]byte(locatorAddress))
+	return filepath.Join(ts.dir, hex.EncodeToString(sum[:])+".json")
+}
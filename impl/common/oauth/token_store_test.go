@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package oauth_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/oauth"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TokenStore", func() {
+
+	var (
+		dir        string
+		tokenStore TokenStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "tokens")
+		Expect(err).NotTo(HaveOccurred())
+		tokenStore, err = NewTokenStore(filepath.Join(dir, "tokens"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Requires a directory", func() {
+		_, err := NewTokenStore("")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Finds no token before logging in", func() {
+		_, found := tokenStore.Load("https://locator:7070")
+		Expect(found).To(BeFalse())
+	})
+
+	It("Stores the tokens by locator, readable only by the user", func() {
+		expiry := time.Now().Add(time.Hour).Round(time.Second)
+		token := Token{AccessToken: "access", RefreshToken: "refresh", Expiry: expiry, GrantType: GrantPassword, TokenURL: "https://uaa/oauth/token", ClientID: "gemfire"}
+		Expect(tokenStore.Store("https://locator:7070", token)).To(Succeed())
+
+		stored, found := tokenStore.Load("https://locator:7070")
+		Expect(found).To(BeTrue())
+		Expect(stored.AccessToken).To(Equal("access"))
+		Expect(stored.Expiry.Equal(expiry)).To(BeTrue())
+		_, found = tokenStore.Load("https://other:7070")
+		Expect(found).To(BeFalse())
+
+		files, err := filepath.Glob(filepath.Join(dir, "tokens", "*.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		info, err := os.Stat(files[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("Stores the tokens in the directory named by GEODE_TOKEN_STORE", func() {
+		previous, set := os.LookupEnv("GEODE_TOKEN_STORE")
+		Expect(os.Setenv("GEODE_TOKEN_STORE", filepath.Join(dir, "store"))).To(Succeed())
+		defer func() {
+			if set {
+				os.Setenv("GEODE_TOKEN_STORE", previous)
+			} else {
+				os.Unsetenv("GEODE_TOKEN_STORE")
+			}
+		}()
+
+		environmentStore, err := TokenStoreFromEnvironment()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(environmentStore.Store("https://locator:7070", Token{AccessToken: "access"})).To(Succeed())
+		files, err := filepath.Glob(filepath.Join(dir, "store", "*.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+	})
+})
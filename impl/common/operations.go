@@ -0,0 +1,183 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/cli/cf/errors"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/vito/go-interact/interact/terminal"
+)
+
+// Defaults of the '--timeout' and '--poll-interval' options of '--wait'
+const (
+	DefaultWaitTimeout  = 30 * time.Minute
+	DefaultPollInterval = 2 * time.Second
+)
+
+// operationInProgress is the ClusterManagementResult status code of a running operation
+const operationInProgress = "IN_PROGRESS"
+
+// operationResult holds the fields of a ClusterManagementOperationResult needed to follow an operation
+type operationResult struct {
+	StatusCode    string `json:"statusCode"`
+	StatusMessage string `json:"statusMessage"`
+	OperationID   string `json:"operationId"`
+	Links         struct {
+		Self string `json:"self"`
+	} `json:"links"`
+	OperationResult *struct {
+		Success       *bool  `json:"success"`
+		StatusMessage string `json:"statusMessage"`
+	} `json:"operationResult"`
+}
+
+// OperationStatusEndpoint provides the endpoint reporting the status of the operations started with
+// an endpoint, e.g. 'get rebalance' for 'start rebalance', and the name of its id parameter. Operations
+// are started by posting to a URL under 'operations' whose status is read from the URL of the id
+func OperationStatusEndpoint(commandData *domain.CommandData, startEndpoint domain.RestEndPoint) (statusEndpoint domain.RestEndPoint, idParameter string, found bool) {
+	if !strings.EqualFold(startEndpoint.HTTPMethod, "post") || !strings.Contains(startEndpoint.URL, "/operations/") {
+		return
+	}
+	for _, endpoint := range commandData.AvailableEndpoints {
+		if !strings.EqualFold(endpoint.HTTPMethod, "get") {
+			continue
+		}
+		for _, parameter := range endpoint.Parameters {
+			if parameter.In == "path" && endpoint.URL == startEndpoint.URL+"/{"+parameter.Name+"}" {
+				return endpoint, parameter.Name, true
+			}
+		}
+	}
+	return
+}
+
+// waitForOperation polls the status endpoint of the operation started with the response until it
+// completes, fails or the '--timeout' expires, and provides the last status of the operation
+func (c *commandProcessor) waitForOperation(commandData *domain.CommandData, startResponse string, statusEndpoint domain.RestEndPoint, idParameter string) (string, error) {
+	parameters := commandData.UserCommand.Parameters
+	timeout, interval, err := waitOptions(parameters)
+	if err != nil {
+		return "", err
+	}
+	var started operationResult
+	_ = json.Unmarshal([]byte(startResponse), &started)
+	id := started.OperationID
+	if id == "" && started.Links.Self != "" {
+		id = path.Base(started.Links.Self)
+	}
+	if id == "" {
+		return "", errors.New("Unable to wait for the operation, the response does not identify it: " + startResponse)
+	}
+
+	statusData := *commandData
+	statusData.UserCommand = domain.UserCommand{Command: statusEndpoint.CommandName, Parameters: make(map[string]string)}
+	for name, value := range parameters {
+		statusData.UserCommand.Parameters[name] = value
+	}
+	statusData.UserCommand.Parameters["--"+idParameter] = id
+
+	progress := newOperationProgress(statusEndpoint.CommandName + " " + id)
+	defer progress.done()
+	start := time.Now()
+	for {
+		time.Sleep(interval)
+		urlResponse, err := c.executeCommand(&statusData)
+		if err != nil {
+			return urlResponse, err
+		}
+		var status operationResult
+		_ = json.Unmarshal([]byte(urlResponse), &status)
+		elapsed := time.Since(start)
+		progress.update(status.StatusCode, elapsed)
+		if status.StatusCode != operationInProgress {
+			return urlResponse, operationError(status)
+		}
+		if elapsed >= timeout {
+			return "", &ClusterError{
+				ResultCode: operationInProgress,
+				Message:    fmt.Sprintf("The operation %s did not complete within %s, check it with '%s --%s %s'", id, timeout, statusEndpoint.CommandName, idParameter, id),
+				exitCode:   ExitCodeTimeout,
+			}
+		}
+	}
+}
+
+// operationError reports an operation which completed without success
+func operationError(status operationResult) error {
+	if status.OperationResult == nil || status.OperationResult.Success == nil || *status.OperationResult.Success {
+		return nil
+	}
+	message := status.OperationResult.StatusMessage
+	if message == "" {
+		message = status.StatusMessage
+	}
+	return &ClusterError{ResultCode: status.StatusCode, Message: "The operation failed: " + message, exitCode: ExitCodeServerError}
+}
+
+// waitOptions provides the values of the '--timeout' and '--poll-interval' options
+func waitOptions(parameters map[string]string) (timeout time.Duration, interval time.Duration, err error) {
+	timeout, err = durationOption(parameters, "--timeout", DefaultWaitTimeout)
+	if err != nil {
+		return
+	}
+	interval, err = durationOption(parameters, "--poll-interval", DefaultPollInterval)
+	return
+}
+
+func durationOption(parameters map[string]string, option string, defaultValue time.Duration) (time.Duration, error) {
+	value := GetOption(parameters, []string{option})
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, errors.New("Invalid " + option + " " + value + ", use a positive duration such as 30s or 5m")
+	}
+	return duration, nil
+}
+
+// operationProgress shows the status of an operation on stderr, rewriting a single line on a terminal
+type operationProgress struct {
+	name     string
+	terminal bool
+}
+
+func newOperationProgress(name string) *operationProgress {
+	progress := &operationProgress{name: name, terminal: terminal.IsTerminal(int(os.Stderr.Fd()))}
+	if !progress.terminal {
+		fmt.Fprintf(os.Stderr, "Waiting for %s to complete\n", name)
+	}
+	return progress
+}
+
+func (p *operationProgress) update(status string, elapsed time.Duration) {
+	if p.terminal {
+		fmt.Fprintf(os.Stderr, "\r%s: %s after %s\033[K", p.name, status, elapsed.Round(time.Second))
+	}
+}
+
+func (p *operationProgress) done() {
+	if p.terminal {
+		fmt.Fprintln(os.Stderr)
+	}
+}
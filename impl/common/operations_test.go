@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common_test
+
+import (
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/commonfakes"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/implfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operations", func() {
+
+	var (
+		requester        *implfakes.FakeRequestHelper
+		formatter        *commonfakes.FakeFormatter
+		requestBuilder   *commonfakes.FakeRequestBuilder
+		commandProcessor impl.CommandProcessor
+		commandData      domain.CommandData
+		started          = `{"statusCode":"ACCEPTED","operationId":"op1","links":{"self":"/management/v1/operations/rebalances/op1"}}`
+		inProgress       = `{"statusCode":"IN_PROGRESS","operationId":"op1"}`
+	)
+
+	BeforeEach(func() {
+		var err error
+		requester = new(implfakes.FakeRequestHelper)
+		formatter = new(commonfakes.FakeFormatter)
+		requestBuilder = new(commonfakes.FakeRequestBuilder)
+		commandProcessor, err = NewCommandProcessor(requester.Spy, formatter, requestBuilder.Spy, new(commonfakes.FakeSpecCache))
+		Expect(err).NotTo(HaveOccurred())
+		commandData = domain.CommandData{
+			AvailableEndpoints: map[string]domain.RestEndPoint{
+				"start rebalance": {CommandName: "start rebalance", HTTPMethod: "post", URL: "/v1/operations/rebalances"},
+				"list rebalances": {CommandName: "list rebalances", HTTPMethod: "get", URL: "/v1/operations/rebalances"},
+				"get rebalance": {CommandName: "get rebalance", HTTPMethod: "get", URL: "/v1/operations/rebalances/{id}", JQFilter: ".operationResult",
+					Parameters: []domain.RestAPIParam{{Name: "id", Required: true, In: "path"}}},
+				"list regions": {CommandName: "list regions", HTTPMethod: "get", URL: "/v1/regions"},
+			},
+			UserCommand: domain.UserCommand{Command: "start rebalance", Parameters: map[string]string{"--wait": "", "--poll-interval": "1ms"}},
+		}
+	})
+
+	Context("OperationStatusEndpoint", func() {
+
+		It("Finds the endpoint reporting the status of an operation by its URL", func() {
+			statusEndpoint, idParameter, found := OperationStatusEndpoint(&commandData, commandData.AvailableEndpoints["start rebalance"])
+			Expect(found).To(BeTrue())
+			Expect(statusEndpoint.CommandName).To(Equal("get rebalance"))
+			Expect(idParameter).To(Equal("id"))
+		})
+
+		It("Does not consider other endpoints operations", func() {
+			_, _, found := OperationStatusEndpoint(&commandData, commandData.AvailableEndpoints["list rebalances"])
+			Expect(found).To(BeFalse())
+			_, _, found = OperationStatusEndpoint(&commandData, commandData.AvailableEndpoints["list regions"])
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Context("--wait", func() {
+
+		It("Polls the status of the operation until it completes and shows the final status", func() {
+			completed := `{"statusCode":"OK","operationId":"op1","operationResult":{"success":true}}`
+			requester.ReturnsOnCall(0, started, 202, nil)
+			requester.ReturnsOnCall(1, inProgress, 200, nil)
+			requester.ReturnsOnCall(2, completed, 200, nil)
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requester.CallCount()).To(Equal(3))
+
+			endpoint, statusData := requestBuilder.ArgsForCall(1)
+			Expect(endpoint.CommandName).To(Equal("get rebalance"))
+			Expect(statusData.UserCommand.Parameters["--id"]).To(Equal("op1"))
+			Expect(commandData.UserCommand.Command).To(Equal("start rebalance"))
+
+			urlResponse, _, _, _ := formatter.FormatResponseArgsForCall(0)
+			Expect(urlResponse).To(Equal(completed))
+		})
+
+		It("Reports an operation that completed without success", func() {
+			requester.ReturnsOnCall(0, started, 202, nil)
+			requester.ReturnsOnCall(1, `{"statusCode":"OK","operationResult":{"success":false,"statusMessage":"no members"}}`, 200, nil)
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError("The operation failed: no members"))
+			Expect(ExitCode(err)).To(Equal(ExitCodeServerError))
+			Expect(formatter.FormatResponseCallCount()).To(Equal(1))
+		})
+
+		It("Reports an operation that failed", func() {
+			requester.ReturnsOnCall(0, started, 202, nil)
+			requester.ReturnsOnCall(1, `{"statusCode":"ERROR","statusMessage":"rebalance aborted"}`, 500, nil)
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError("ERROR: rebalance aborted"))
+			Expect(ExitCode(err)).To(Equal(ExitCodeServerError))
+		})
+
+		It("Gives up after the timeout", func() {
+			commandData.UserCommand.Parameters["--timeout"] = "5ms"
+			requester.ReturnsOnCall(0, started, 202, nil)
+			requester.Returns(inProgress, 200, nil)
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError(ContainSubstring("The operation op1 did not complete within 5ms")))
+			Expect(ExitCode(err)).To(Equal(ExitCodeTimeout))
+			Expect(formatter.FormatResponseCallCount()).To(BeZero())
+		})
+
+		It("Takes the id of the operation from its link", func() {
+			requester.ReturnsOnCall(0, `{"statusCode":"ACCEPTED","links":{"self":"/management/v1/operations/rebalances/op2"}}`, 202, nil)
+			requester.ReturnsOnCall(1, `{"statusCode":"OK"}`, 200, nil)
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			_, statusData := requestBuilder.ArgsForCall(1)
+			Expect(statusData.UserCommand.Parameters["--id"]).To(Equal("op2"))
+		})
+
+		It("Rejects invalid durations", func() {
+			commandData.UserCommand.Parameters["--poll-interval"] = "often"
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError(ContainSubstring("Invalid --poll-interval often")))
+			Expect(requester.CallCount()).To(BeZero())
+		})
+
+		It("Only applies to commands which start an operation", func() {
+			commandData.UserCommand.Command = "list regions"
+			err := commandProcessor.ProcessCommand(&commandData)
+			Expect(err).To(MatchError(ContainSubstring("--wait applies to commands which start an operation")))
+			Expect(requester.CallCount()).To(BeZero())
+		})
+	})
+})
@@ -16,30 +16,53 @@
 package common
 
 import (
+	"errors"
 	"os"
 	"strings"
 
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
 )
 
+// targetlessCommands of the standalone client may be given without a target, which is then taken
+// from the environment or the current profile if set
+var targetlessCommands = []string{"completion", "config"}
+
 // GetTargetAndClusterCommand extracts the target and command from the args and environment variables
 func GetTargetAndClusterCommand(args []string) (target string, userCommand domain.UserCommand) {
+	return splitTargetAndCommand(args, os.Getenv("GEODE_TARGET"), nil)
+}
+
+// SplitTargetAndCommand extracts the target and command from the args of the standalone client. A
+// default target, e.g. from the environment, means that the args only start with a target when it
+// is the same one
+func SplitTargetAndCommand(args []string, defaultTarget string) (target string, userCommand domain.UserCommand) {
+	return splitTargetAndCommand(args, defaultTarget, targetlessCommands)
+}
+
+func splitTargetAndCommand(args []string, defaultTarget string, targetless []string) (target string, userCommand domain.UserCommand) {
 	if len(args) < 2 {
 		return
 	}
-	target = os.Getenv("GEODE_TARGET")
+	target = defaultTarget
 	commandStart := 2
-	if target == "" && !strings.HasPrefix(args[1], "-") {
+	if Contains(targetless, args[1]) {
+		commandStart = 1
+	} else if target == "" && !strings.HasPrefix(args[1], "-") {
 		target = args[1]
 	} else if target != args[1] {
 		commandStart = 1
 	}
 
+	userCommand = ParseUserCommand(args[commandStart:])
+	return
+}
+
+// ParseUserCommand extracts the command name and its options from the words following the target
+func ParseUserCommand(args []string) (userCommand domain.UserCommand) {
 	userCommand.Parameters = make(map[string]string)
 	// find the command name before the options
 	var option = ""
-	for i := commandStart; i < len(args); i++ {
-		token := args[i]
+	for _, token := range args {
 		if strings.HasPrefix(token, "-") {
 			if option != "" {
 				userCommand.Parameters[option] = ""
@@ -59,6 +82,48 @@ func GetTargetAndClusterCommand(args []string) (target string, userCommand domai
 	return
 }
 
+// SplitCommandLine splits a line typed by the user into words. Words may be quoted with single
+// or double quotes and characters may be escaped with a backslash outside of single quotes.
+// The words found so far are returned along with an error when a quote is not terminated
+func SplitCommandLine(line string) (words []string, err error) {
+	var word strings.Builder
+	var quote rune
+	inWord, escaped := false, false
+	for _, char := range line {
+		switch {
+		case escaped:
+			word.WriteRune(char)
+			escaped = false
+		case char == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else {
+				word.WriteRune(char)
+			}
+		case char == '\'' || char == '"':
+			quote, inWord = char, true
+		case char == ' ' || char == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(char)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	if quote != 0 {
+		err = errors.New("unterminated quote in: " + line)
+	}
+	return
+}
+
 // HasOption checks if a option has been passed in on the command line
 func HasOption(parameters map[string]string, options []string) bool {
 	for _, option := range options {
@@ -81,6 +146,15 @@ func GetOption(parameters map[string]string, options []string) string {
 	return ""
 }
 
+// GetOptionOrEnv retrieves an option from the map of parameters, falling back to an environment
+// variable
+func GetOptionOrEnv(parameters map[string]string, option string, variable string) string {
+	if value := GetOption(parameters, []string{option}); value != "" {
+		return value
+	}
+	return os.Getenv(variable)
+}
+
 func Contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
@@ -64,6 +64,32 @@ var _ = Describe("Parser", func() {
 				Expect(len(userCommand.Parameters)).To(Equal(0))
 			})
 
+			It("uses the default target when the args do not start with it", func() {
+				args = []string{"program", "list", "members"}
+				target, userCommand := common.SplitTargetAndCommand(args, "default")
+				Expect(target).To(Equal("default"))
+				Expect(userCommand.Command).To(Equal("list members"))
+
+				args = []string{"program", "default", "list", "members"}
+				target, userCommand = common.SplitTargetAndCommand(args, "default")
+				Expect(target).To(Equal("default"))
+				Expect(userCommand.Command).To(Equal("list members"))
+			})
+
+			It("returns no target for a standalone command that does not need one", func() {
+				args = []string{"program", "completion", "bash"}
+				target, userCommand := common.SplitTargetAndCommand(args, "")
+				Expect(target).To(Equal(""))
+				Expect(userCommand.Command).To(Equal("completion bash"))
+			})
+
+			It("takes the names of standalone commands as targets in plugin mode", func() {
+				args = []string{"program", "config", "list", "regions"}
+				target, userCommand := common.GetTargetAndClusterCommand(args)
+				Expect(target).To(Equal("config"))
+				Expect(userCommand.Command).To(Equal("list regions"))
+			})
+
 			It("returns target, multiple word command and options ", func() {
 				args = []string{"program", "target", "list", "members", "-h"}
 				target, userCommand := common.GetTargetAndClusterCommand(args)
@@ -238,4 +264,38 @@ var _ = Describe("Parser", func() {
 
 		})
 	})
+
+	Context("SplitCommandLine", func() {
+		It("splits words separated by whitespace", func() {
+			words, err := common.SplitCommandLine("  list  region\tindexes --regionName r1 ")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(words).To(Equal([]string{"list", "region", "indexes", "--regionName", "r1"}))
+		})
+
+		It("keeps quoted words together", func() {
+			words, err := common.SplitCommandLine(`create region --regionConfig '{"name": "a b"}' -t ".result[] | .id"`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(words).To(Equal([]string{"create", "region", "--regionConfig", `{"name": "a b"}`, "-t", ".result[] | .id"}))
+		})
+
+		It("supports escaped characters and empty quoted words", func() {
+			words, err := common.SplitCommandLine(`get region --id my\ region --group ""`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(words).To(Equal([]string{"get", "region", "--id", "my region", "--group", ""}))
+		})
+
+		It("returns an error for an unterminated quote", func() {
+			words, err := common.SplitCommandLine(`get region --id "abc`)
+			Expect(err).To(HaveOccurred())
+			Expect(words).To(Equal([]string{"get", "region", "--id", "abc"}))
+		})
+	})
+
+	Context("ParseUserCommand", func() {
+		It("separates the command name from its options", func() {
+			userCommand := common.ParseUserCommand([]string{"list", "region", "indexes", "--regionName", "r1", "-t"})
+			Expect(userCommand.Command).To(Equal("list region indexes"))
+			Expect(userCommand.Parameters).To(Equal(map[string]string{"--regionName": "r1", "-t": ""}))
+		})
+	})
 })
@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"code.cloudfoundry.org/cli/cf/errors"
+	"github.com/vito/go-interact/interact"
+	"github.com/vito/go-interact/interact/terminal"
+)
+
+// ReadPassword provides the password given with the '--password-file' option or, with the
+// '--password-stdin' option, the first line of the input. given is false without these options
+func ReadPassword(parameters map[string]string, input io.Reader) (password string, given bool, err error) {
+	passwordFile := GetOption(parameters, []string{"--password-file"})
+	passwordStdin := HasOption(parameters, []string{"--password-stdin"})
+	switch {
+	case passwordFile != "" && passwordStdin:
+		return "", true, errors.New("Use either --password-file or --password-stdin")
+	case passwordFile != "":
+		content, err := ioutil.ReadFile(passwordFile)
+		if err != nil {
+			return "", true, errors.New("Unable to read the password file: " + err.Error())
+		}
+		return firstLine(string(content)), true, nil
+	case passwordStdin:
+		content, err := readLine(input)
+		if err != nil {
+			return "", true, errors.New("Unable to read the password from stdin: " + err.Error())
+		}
+		return firstLine(content), true, nil
+	}
+	return "", false, nil
+}
+
+// PromptPassword asks for the password of a user without echoing it. It provides no password
+// unless stdin is a terminal, so that scripts never block waiting for input
+func PromptPassword(username string) (string, error) {
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return "", nil
+	}
+	interaction := interact.NewInteraction("Password for " + username)
+	interaction.Output = os.Stderr
+	var password interact.Password
+	err := interaction.Resolve(interact.Required(&password))
+	if err != nil {
+		return "", errors.New("Unable to read the password: " + err.Error())
+	}
+	return string(password), nil
+}
+
+// readLine reads byte by byte so that the rest of the input is left, e.g. for the commands of a shell
+func readLine(input io.Reader) (string, error) {
+	var line []byte
+	buffer := make([]byte, 1)
+	for {
+		n, err := input.Read(buffer)
+		if n > 0 {
+			if buffer[0] == '\n' {
+				return string(line), nil
+			}
+			line = append(line, buffer[0])
+		}
+		if err == io.EOF {
+			return string(line), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+func firstLine(content string) string {
+	return strings.TrimRight(strings.SplitN(content, "\n", 2)[0], "\r")
+}
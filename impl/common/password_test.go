@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Password", func() {
+
+	var (
+		dir        string
+		parameters map[string]string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "password")
+		Expect(err).NotTo(HaveOccurred())
+		parameters = map[string]string{}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Provides no password without the options", func() {
+		_, given, err := ReadPassword(parameters, strings.NewReader("secret\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(given).To(BeFalse())
+	})
+
+	It("Reads the first line of the password file", func() {
+		passwordFile := filepath.Join(dir, "password")
+		Expect(ioutil.WriteFile(passwordFile, []byte("secret\r\n"), 0600)).To(Succeed())
+		parameters["--password-file"] = passwordFile
+		password, given, err := ReadPassword(parameters, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(given).To(BeTrue())
+		Expect(password).To(Equal("secret"))
+	})
+
+	It("Reports a missing password file", func() {
+		parameters["--password-file"] = filepath.Join(dir, "missing")
+		_, _, err := ReadPassword(parameters, nil)
+		Expect(err).To(MatchError(ContainSubstring("Unable to read the password file")))
+	})
+
+	It("Reads the first line of stdin and leaves the rest", func() {
+		parameters["--password-stdin"] = ""
+		input := strings.NewReader("secret\nlist regions\n")
+		password, given, err := ReadPassword(parameters, input)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(given).To(BeTrue())
+		Expect(password).To(Equal("secret"))
+		rest, err := ioutil.ReadAll(input)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(rest)).To(Equal("list regions\n"))
+	})
+
+	It("Reads a password without a line break from stdin", func() {
+		parameters["--password-stdin"] = ""
+		password, _, err := ReadPassword(parameters, strings.NewReader("secret"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(password).To(Equal("secret"))
+	})
+
+	It("Rejects both options", func() {
+		parameters["--password-stdin"] = ""
+		parameters["--password-file"] = "password"
+		_, _, err := ReadPassword(parameters, strings.NewReader("secret"))
+		Expect(err).To(HaveOccurred())
+	})
+})
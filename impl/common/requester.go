@@ -16,25 +16,100 @@
 package common
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
 )
 
-// Exchange implements the impl.RequestHelper function type
-var Exchange = func(request *http.Request) (urlResponse string, statusCode int, err error) {
-	transport := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
-	client := &http.Client{Transport: transport}
+type responseHeaderKey struct{}
+
+// WithResponseHeader returns a copy of the request which asks Exchange to copy the
+// headers of the response into the given header
+func WithResponseHeader(request *http.Request, header http.Header) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), responseHeaderKey{}, header))
+}
+
+// Exchange implements the impl.RequestHelper function type. The locator's certificate is verified
+// unless the connection data attached with WithConnectionData asks to skip validation
+var Exchange = NewExchange(nil)
 
-	resp, err := client.Do(request)
+// NewExchange provides an implementation of the impl.RequestHelper function type which falls back
+// to the known locators when the certificate of a locator cannot be verified. Without known
+// locators it behaves like Exchange
+func NewExchange(knownLocators KnownLocators) impl.RequestHelper {
+	return func(request *http.Request) (urlResponse string, statusCode int, err error) {
+		connectionData, _ := request.Context().Value(connectionDataKey{}).(domain.ConnectionData)
+		tlsConfig, err := TLSConfig(connectionData)
+		if err != nil {
+			return "", 0, err
+		}
+		// an explicitly configured CA is never bypassed
+		if knownLocators != nil && !tlsConfig.InsecureSkipVerify && connectionData.CACert == "" {
+			TrustOnFirstUse(tlsConfig, request.URL.Host, knownLocators)
+		}
+		transport := &http.Transport{TLSClientConfig: tlsConfig}
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Do(request)
+		if err != nil {
+			return "", 0, err
+		}
+		// an expired token is renewed and the request retried once, if its body can be sent again
+		if resp.StatusCode == http.StatusUnauthorized && connectionData.TokenRenewer != nil &&
+			strings.HasPrefix(request.Header.Get("Authorization"), "Bearer ") && replayable(request) {
+			resp.Body.Close()
+			request, err = withRenewedToken(request, connectionData.TokenRenewer)
+			if err != nil {
+				return "", 0, err
+			}
+			resp, err = client.Do(request)
+			if err != nil {
+				return "", 0, err
+			}
+		}
+		defer resp.Body.Close()
+
+		if header, ok := request.Context().Value(responseHeaderKey{}).(http.Header); ok {
+			for key, values := range resp.Header {
+				header[key] = values
+			}
+		}
+
+		urlResponse, err = getURLOutput(resp)
+		statusCode = resp.StatusCode
+		return
+	}
+}
+
+// withRenewedToken copies a request, including its body, with the Authorization header of a new token
+func withRenewedToken(request *http.Request, tokenRenewer domain.TokenRenewer) (*http.Request, error) {
+	token, err := tokenRenewer.RenewToken()
+	if err != nil {
+		return nil, err
+	}
+	authorization, err := BearerToken(domain.ConnectionData{Token: token})
 	if err != nil {
-		return "", 0, err
+		return nil, err
 	}
+	retry := request.Clone(request.Context())
+	if request.GetBody != nil {
+		retry.Body, err = request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+	retry.Header.Set("Authorization", authorization)
+	return retry, nil
+}
 
-	urlResponse, err = getURLOutput(resp)
-	statusCode = resp.StatusCode
-	return
+// replayable reports whether the body of a request, if any, can be read again for a retry
+func replayable(request *http.Request) bool {
+	return request.Body == nil || request.Body == http.NoBody || request.GetBody != nil
 }
 
 func getURLOutput(resp *http.Response) (urlResponse string, err error) {
@@ -0,0 +1,154 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain/domainfakes"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/builder"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Exchange", func() {
+
+	var (
+		server         *httptest.Server
+		authorizations []string
+		bodies         []string
+		tokenRenewer   *domainfakes.FakeTokenRenewer
+		connectionData domain.ConnectionData
+	)
+
+	BeforeEach(func() {
+		authorizations = nil
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			authorizations = append(authorizations, request.Header.Get("Authorization"))
+			bodies = append(bodies, string(body))
+			if request.Header.Get("Authorization") != "Bearer fresh-token" {
+				writer.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = writer.Write([]byte("created"))
+		}))
+		tokenRenewer = new(domainfakes.FakeTokenRenewer)
+		tokenRenewer.RenewTokenReturns("bearer fresh-token", nil)
+		connectionData = domain.ConnectionData{Token: "expired-token", TokenRenewer: tokenRenewer}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	exchange := func(authorization string) (string, int, error) {
+		request, err := http.NewRequest("POST", server.URL, strings.NewReader(`{"name":"region1"}`))
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Authorization", authorization)
+		return Exchange(WithConnectionData(request, connectionData))
+	}
+
+	It("Renews a rejected token and retries the request once", func() {
+		urlResponse, statusCode, err := exchange("Bearer expired-token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusOK))
+		Expect(urlResponse).To(Equal("created"))
+		Expect(tokenRenewer.RenewTokenCallCount()).To(Equal(1))
+		Expect(authorizations).To(Equal([]string{"Bearer expired-token", "Bearer fresh-token"}))
+		Expect(bodies).To(Equal([]string{`{"name":"region1"}`, `{"name":"region1"}`}))
+	})
+
+	Context("The body is read from a file", func() {
+
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "requester")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "region.json"), []byte(`{"name":"region1"}`), 0600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("Sends the body of an @file parameter again with the renewed token", func() {
+			endPoint := domain.RestEndPoint{HTTPMethod: "post", Parameters: []domain.RestAPIParam{{Name: "regionConfig", In: "body"}}}
+			connectionData.LocatorAddress = server.URL
+			connectionData.UseToken = true
+			commandData := domain.CommandData{ConnnectionData: connectionData, UserCommand: domain.UserCommand{
+				Parameters: map[string]string{"--regionConfig": "@" + filepath.Join(dir, "region.json")}}}
+			request, err := builder.BuildRequest(endPoint, &commandData)
+			Expect(err).NotTo(HaveOccurred())
+			_, statusCode, err := Exchange(request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusCode).To(Equal(http.StatusOK))
+			Expect(bodies).To(Equal([]string{`{"name":"region1"}`, `{"name":"region1"}`}))
+		})
+
+		It("Reports the rejection when the body cannot be sent again", func() {
+			file, err := os.Open(filepath.Join(dir, "region.json"))
+			Expect(err).NotTo(HaveOccurred())
+			request, err := http.NewRequest("POST", server.URL, file)
+			Expect(err).NotTo(HaveOccurred())
+			request.Header.Set("Authorization", "Bearer expired-token")
+			_, statusCode, err := Exchange(WithConnectionData(request, connectionData))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statusCode).To(Equal(http.StatusUnauthorized))
+			Expect(tokenRenewer.RenewTokenCallCount()).To(Equal(0))
+		})
+	})
+
+	It("Reports the rejection when the renewed token is rejected as well", func() {
+		tokenRenewer.RenewTokenReturns("still-rejected", nil)
+		_, statusCode, err := exchange("Bearer expired-token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusUnauthorized))
+		Expect(authorizations).To(HaveLen(2))
+	})
+
+	It("Reports a failure to renew the token", func() {
+		tokenRenewer.RenewTokenReturns("", NewAuthError("run 'cf login'"))
+		_, _, err := exchange("Bearer expired-token")
+		Expect(err).To(MatchError("run 'cf login'"))
+		Expect(authorizations).To(HaveLen(1))
+	})
+
+	It("Does not renew tokens for basic authentication", func() {
+		_, statusCode, err := exchange("Basic YWRtaW46cGFzc3dvcmQ=")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusUnauthorized))
+		Expect(tokenRenewer.RenewTokenCallCount()).To(Equal(0))
+	})
+
+	It("Does not retry without a token renewer", func() {
+		connectionData.TokenRenewer = nil
+		_, statusCode, err := exchange("Bearer expired-token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusCode).To(Equal(http.StatusUnauthorized))
+		Expect(authorizations).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,204 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/vito/go-interact/interact/terminal"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/completion"
+)
+
+// Help describes how to use the shell
+const Help = "Enter a command with its options, 'commands' lists the available commands, " +
+	"<command> -h shows its options and 'exit' leaves the shell."
+
+var builtinCommands = []string{"commands", "exit", "help", "quit"}
+
+// lineReader provides the lines typed by the user, returning io.EOF when input ends
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+// Shell runs commands against a cluster in a loop, reusing its connection data and endpoints
+type Shell struct {
+	comm   impl.CommandProcessor
+	input  io.Reader
+	output io.Writer
+}
+
+// New provides a constructor for the interactive shell
+func New(comm impl.CommandProcessor, input io.Reader, output io.Writer) (*Shell, error) {
+	if comm == nil {
+		return nil, errors.New("command processor is not valid")
+	}
+	return &Shell{comm: comm, input: input, output: output}, nil
+}
+
+// Run discovers the endpoints of the cluster once and then executes the commands typed by the
+// user until input ends or the user exits. Failed commands are reported without ending the session
+func (s *Shell) Run(commandData *domain.CommandData) error {
+	err := s.comm.LoadEndPoints(commandData)
+	if err != nil {
+		return err
+	}
+
+	reader := s.newLineReader(commandData)
+	fmt.Fprintln(s.output, Help)
+	for {
+		line, err := reader.ReadLine()
+		if err == terminal.ErrKeyboardInterrupt {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		words, err := common.SplitCommandLine(line)
+		if err != nil {
+			fmt.Fprintln(s.output, err.Error())
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		switch words[0] {
+		case "exit", "quit":
+			return nil
+		case "help":
+			fmt.Fprintln(s.output, Help)
+			continue
+		}
+
+		commandData.UserCommand = common.ParseUserCommand(words)
+		err = s.comm.ProcessCommand(commandData)
+		if err != nil {
+			fmt.Fprintln(s.output, err.Error())
+		}
+	}
+}
+
+// newLineReader provides line editing, history and tab completion when the input is a terminal,
+// and reads plain lines otherwise, e.g. when commands are piped into the shell
+func (s *Shell) newLineReader(commandData *domain.CommandData) lineReader {
+	file, isFile := s.input.(*os.File)
+	if !isFile || !terminal.IsTerminal(int(file.Fd())) {
+		return &plainReader{scanner: bufio.NewScanner(s.input)}
+	}
+
+	fd := int(file.Fd())
+	term := terminal.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{s.input, s.output}, commandData.Target+"> ")
+	if width, height, err := terminal.GetSize(fd); err == nil {
+		_ = term.SetSize(width, height)
+	}
+	term.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return complete(term, commandData.AvailableEndpoints, line, pos)
+	}
+	return &terminalReader{fd: fd, term: term}
+}
+
+// complete replaces the word before the cursor with its completion, or with the longest common
+// prefix of the candidates while listing them when there is more than one
+func complete(output io.Writer, endpoints map[string]domain.RestEndPoint, line string, pos int) (string, int, bool) {
+	beforeCursor := line[:pos]
+	words, _ := common.SplitCommandLine(beforeCursor)
+	if len(words) == 0 || strings.HasSuffix(beforeCursor, " ") {
+		words = append(words, "")
+	}
+	partial := words[len(words)-1]
+	candidates := completion.Candidates(endpoints, words)
+	if len(words) == 1 {
+		for _, builtin := range builtinCommands {
+			if strings.HasPrefix(builtin, partial) {
+				candidates = append(candidates, builtin)
+			}
+		}
+		sort.Strings(candidates)
+	}
+	if len(candidates) == 0 || !strings.HasSuffix(beforeCursor, partial) {
+		return "", 0, false
+	}
+
+	replacement := candidates[0] + " "
+	if len(candidates) > 1 {
+		replacement = commonPrefix(candidates)
+		if replacement == partial {
+			fmt.Fprintln(output, strings.Join(candidates, "  "))
+			return line, pos, true
+		}
+	}
+	start := pos - len(partial)
+	return line[:start] + replacement + line[pos:], start + len(replacement), true
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+type plainReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *plainReader) ReadLine() (string, error) {
+	if !r.scanner.Scan() {
+		if r.scanner.Err() != nil {
+			return "", r.scanner.Err()
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// terminalReader only keeps the terminal in raw mode while a line is being typed, so that the
+// output of commands is printed normally
+type terminalReader struct {
+	fd   int
+	term *terminal.Terminal
+}
+
+func (r *terminalReader) ReadLine() (string, error) {
+	state, err := terminal.MakeRaw(r.fd)
+	if err != nil {
+		return "", err
+	}
+	defer terminal.Restore(r.fd, state)
+	return r.term.ReadLine()
+}
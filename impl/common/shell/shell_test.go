@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package shell_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/shell"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/implfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shell", func() {
+
+	var (
+		comm        *implfakes.FakeCommandProcessor
+		output      *bytes.Buffer
+		commandData domain.CommandData
+	)
+
+	BeforeEach(func() {
+		comm = new(implfakes.FakeCommandProcessor)
+		output = &bytes.Buffer{}
+		commandData = domain.CommandData{Target: "https://some.geode-locator.com"}
+	})
+
+	run := func(input string) error {
+		interactive, err := shell.New(comm, strings.NewReader(input), output)
+		Expect(err).NotTo(HaveOccurred())
+		return interactive.Run(&commandData)
+	}
+
+	It("Requires a command processor", func() {
+		interactive, err := shell.New(nil, strings.NewReader(""), output)
+		Expect(err).To(HaveOccurred())
+		Expect(interactive).To(BeNil())
+	})
+
+	It("Loads the endpoints once and processes every command", func() {
+		err := run("list regions --id r1\n\n  list members -t\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(comm.LoadEndPointsCallCount()).To(Equal(1))
+		Expect(comm.ProcessCommandCallCount()).To(Equal(2))
+		Expect(commandData.UserCommand.Command).To(Equal("list members"))
+		Expect(commandData.UserCommand.Parameters).To(HaveKey("-t"))
+	})
+
+	It("Reports failed commands and continues", func() {
+		comm.ProcessCommandReturnsOnCall(0, errors.New("ENTITY_NOT_FOUND: Region 'r1' does not exist."))
+		err := run("get region --id r1\nping\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(comm.ProcessCommandCallCount()).To(Equal(2))
+		Expect(output.String()).To(ContainSubstring("ENTITY_NOT_FOUND: Region 'r1' does not exist."))
+	})
+
+	It("Stops at exit", func() {
+		err := run("ping\nexit\nping\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(comm.ProcessCommandCallCount()).To(Equal(1))
+	})
+
+	It("Reports lines that cannot be split into words", func() {
+		err := run("get region --id 'r1\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(comm.ProcessCommandCallCount()).To(BeZero())
+		Expect(output.String()).To(ContainSubstring("unterminated quote"))
+	})
+
+	It("Returns the error when the endpoints cannot be loaded", func() {
+		comm.LoadEndPointsReturns(errors.New("Unable to reach locator"))
+		err := run("ping\n")
+		Expect(err).To(MatchError("Unable to reach locator"))
+		Expect(comm.ProcessCommandCallCount()).To(BeZero())
+	})
+})
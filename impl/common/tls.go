@@ -0,0 +1,196 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/cli/cf/errors"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"golang.org/x/crypto/pkcs12"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . KnownLocators
+
+// KnownLocators records the certificate fingerprints of the locators trusted on first use
+type KnownLocators interface {
+	// Lookup provides the fingerprint recorded for a locator address
+	Lookup(address string) (fingerprint string, known bool, err error)
+	// Accept asks whether to trust a locator contacted for the first time and records its
+	// fingerprint when it is trusted
+	Accept(address string, fingerprint string) (bool, error)
+}
+
+type connectionDataKey struct{}
+
+// WithConnectionData returns a copy of the request which asks Exchange to secure the
+// connection as described by the connection data
+func WithConnectionData(request *http.Request, connectionData domain.ConnectionData) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), connectionDataKey{}, connectionData))
+}
+
+// ApplyTLSOptions sets the TLS settings of the connection from the '--ca-cert', '--client-cert',
+// '--client-key', '--client-cert-passphrase' and '--skip-ssl-validation' options, falling back
+// to the 'GEODE_CA_CERT', 'GEODE_CLIENT_CERT', 'GEODE_CLIENT_KEY' and
+// 'GEODE_CLIENT_CERT_PASSPHRASE' environment variables
+func ApplyTLSOptions(commandData *domain.CommandData) {
+	parameters := commandData.UserCommand.Parameters
+	connectionData := &commandData.ConnnectionData
+	connectionData.CACert = GetOptionOrEnv(parameters, "--ca-cert", "GEODE_CA_CERT")
+	connectionData.ClientCert = GetOptionOrEnv(parameters, "--client-cert", "GEODE_CLIENT_CERT")
+	connectionData.ClientKey = GetOptionOrEnv(parameters, "--client-key", "GEODE_CLIENT_KEY")
+	connectionData.ClientCertPassphrase = GetOptionOrEnv(parameters, "--client-cert-passphrase", "GEODE_CLIENT_CERT_PASSPHRASE")
+	connectionData.SkipSSLValidation = HasOption(parameters, []string{"--skip-ssl-validation"})
+}
+
+// TLSConfig provides the TLS client configuration for a connection. Certificates are verified
+// against the system certificates and the CA certificate of the connection unless validation
+// is skipped, and the client certificate is presented when the locator asks for one
+func TLSConfig(connectionData domain.ConnectionData) (*tls.Config, error) {
+	config := &tls.Config{}
+	if connectionData.ClientCert != "" {
+		certificate, err := clientCertificate(connectionData)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	} else if connectionData.ClientKey != "" {
+		return nil, errors.New("A client key requires a client certificate")
+	}
+
+	if connectionData.SkipSSLValidation {
+		config.InsecureSkipVerify = true
+		return config, nil
+	}
+	if connectionData.CACert == "" {
+		return config, nil
+	}
+
+	pem, err := ioutil.ReadFile(connectionData.CACert)
+	if err != nil {
+		return nil, errors.New("Unable to read the CA certificate: " + err.Error())
+	}
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(pem) {
+		return nil, errors.New("No PEM encoded certificates found in " + connectionData.CACert)
+	}
+	config.RootCAs = rootCAs
+	return config, nil
+}
+
+// clientCertificate loads a PEM certificate with the key in the same or a separate file, or a
+// PKCS#12 bundle
+func clientCertificate(connectionData domain.ConnectionData) (tls.Certificate, error) {
+	content, err := ioutil.ReadFile(connectionData.ClientCert)
+	if err != nil {
+		return tls.Certificate{}, errors.New("Unable to read the client certificate: " + err.Error())
+	}
+
+	keyContent := content
+	if block, _ := pem.Decode(content); block == nil {
+		blocks, err := pkcs12.ToPEM(content, connectionData.ClientCertPassphrase)
+		if err != nil {
+			return tls.Certificate{}, errors.New("Unable to decode the PKCS#12 client certificate: " + err.Error())
+		}
+		content = nil
+		for _, block := range blocks {
+			content = append(content, pem.EncodeToMemory(block)...)
+		}
+		keyContent = content
+	} else if connectionData.ClientKey != "" {
+		keyContent, err = ioutil.ReadFile(connectionData.ClientKey)
+		if err != nil {
+			return tls.Certificate{}, errors.New("Unable to read the client key: " + err.Error())
+		}
+	}
+
+	certificate, err := tls.X509KeyPair(content, keyContent)
+	if err != nil {
+		return tls.Certificate{}, errors.New("Invalid client certificate: " + err.Error())
+	}
+	return certificate, nil
+}
+
+// TrustOnFirstUse changes a TLS configuration to accept a certificate that cannot be verified when
+// its fingerprint is the one recorded for the locator, or when the user trusts a locator contacted
+// for the first time. A certificate that differs from the recorded one is always rejected
+func TrustOnFirstUse(config *tls.Config, address string, knownLocators KnownLocators) {
+	rootCAs := config.RootCAs
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return errors.New("The locator " + address + " did not present a certificate")
+		}
+		intermediates := x509.NewCertPool()
+		for _, certificate := range state.PeerCertificates[1:] {
+			intermediates.AddCert(certificate)
+		}
+		_, verifyErr := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+			DNSName:       state.ServerName,
+			Roots:         rootCAs,
+			Intermediates: intermediates,
+		})
+		if verifyErr == nil {
+			return nil
+		}
+
+		fingerprint := Fingerprint(state.PeerCertificates[0])
+		recorded, known, err := knownLocators.Lookup(address)
+		if err != nil {
+			return err
+		}
+		if known {
+			if recorded == fingerprint {
+				return nil
+			}
+			return errors.New("WARNING: THE CERTIFICATE OF LOCATOR " + address + " HAS CHANGED!\n" +
+				"Someone could be intercepting the connection, or the certificate has been replaced.\n" +
+				"Recorded fingerprint: " + recorded + "\n" +
+				"Received fingerprint: " + fingerprint + "\n" +
+				"Remove the entry of " + address + " from the known locators if the change is expected")
+		}
+		accepted, err := knownLocators.Accept(address, fingerprint)
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return errors.New("The certificate of locator " + address + " is not trusted: " + verifyErr.Error())
+		}
+		return nil
+	}
+}
+
+// Fingerprint is the SHA-256 fingerprint of a certificate in the form printed by
+// 'openssl x509 -fingerprint -sha256'
+func Fingerprint(certificate *x509.Certificate) string {
+	sum := sha256.Sum256(certificate.Raw)
+	pairs := make([]string, len(sum))
+	for index, value := range sum {
+		pairs[index] = fmt.Sprintf("%02X", value)
+	}
+	return "SHA256:" + strings.Join(pairs, ":")
+}
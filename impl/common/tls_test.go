@@ -0,0 +1,318 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package common_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/commonfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLS", func() {
+
+	var (
+		server      *httptest.Server
+		dir         string
+		caCertPath  string
+		commandData domain.CommandData
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte("pong"))
+		}))
+		var err error
+		dir, err = ioutil.TempDir("", "tls")
+		Expect(err).NotTo(HaveOccurred())
+		caCertPath = filepath.Join(dir, "ca.pem")
+		certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		Expect(ioutil.WriteFile(caCertPath, certificate, 0600)).To(Succeed())
+		commandData = domain.CommandData{UserCommand: domain.UserCommand{Parameters: map[string]string{}}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(dir)
+	})
+
+	exchange := func(connectionData domain.ConnectionData) (string, error) {
+		request, err := http.NewRequest("GET", server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		urlResponse, _, err := Exchange(WithConnectionData(request, connectionData))
+		return urlResponse, err
+	}
+
+	Context("Exchange", func() {
+		It("Verifies the certificate by default", func() {
+			_, err := exchange(domain.ConnectionData{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("certificate"))
+		})
+
+		It("Verifies the certificate when the connection data is not attached", func() {
+			request, _ := http.NewRequest("GET", server.URL, nil)
+			_, _, err := Exchange(request)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Trusts the CA certificate", func() {
+			urlResponse, err := exchange(domain.ConnectionData{CACert: caCertPath})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(urlResponse).To(Equal("pong"))
+		})
+
+		It("Skips validation when asked to", func() {
+			urlResponse, err := exchange(domain.ConnectionData{SkipSSLValidation: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(urlResponse).To(Equal("pong"))
+		})
+
+		It("Reports a CA certificate that cannot be read", func() {
+			_, err := exchange(domain.ConnectionData{CACert: filepath.Join(dir, "missing.pem")})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unable to read the CA certificate"))
+		})
+
+		It("Reports a CA certificate file without certificates", func() {
+			Expect(ioutil.WriteFile(caCertPath, []byte("not a certificate"), 0600)).To(Succeed())
+			_, err := exchange(domain.ConnectionData{CACert: caCertPath})
+			Expect(err).To(MatchError("No PEM encoded certificates found in " + caCertPath))
+		})
+	})
+
+	Context("Trust on first use", func() {
+
+		var (
+			knownLocators *commonfakes.FakeKnownLocators
+			address       string
+			fingerprint   string
+		)
+
+		BeforeEach(func() {
+			knownLocators = new(commonfakes.FakeKnownLocators)
+			address = strings.TrimPrefix(server.URL, "https://")
+			fingerprint = Fingerprint(server.Certificate())
+		})
+
+		trustingExchange := func(connectionData domain.ConnectionData) (string, error) {
+			request, err := http.NewRequest("GET", server.URL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			urlResponse, _, err := NewExchange(knownLocators)(WithConnectionData(request, connectionData))
+			return urlResponse, err
+		}
+
+		It("Formats fingerprints like openssl", func() {
+			Expect(fingerprint).To(MatchRegexp("^SHA256(:[0-9A-F]{2}){32}$"))
+		})
+
+		It("Asks to trust a locator contacted for the first time", func() {
+			knownLocators.AcceptReturns(true, nil)
+			urlResponse, err := trustingExchange(domain.ConnectionData{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(urlResponse).To(Equal("pong"))
+			Expect(knownLocators.AcceptCallCount()).To(Equal(1))
+			acceptedAddress, acceptedFingerprint := knownLocators.AcceptArgsForCall(0)
+			Expect(acceptedAddress).To(Equal(address))
+			Expect(acceptedFingerprint).To(Equal(fingerprint))
+		})
+
+		It("Fails when the user does not trust the locator", func() {
+			knownLocators.AcceptReturns(false, nil)
+			_, err := trustingExchange(domain.ConnectionData{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("The certificate of locator " + address + " is not trusted"))
+		})
+
+		It("Accepts the recorded certificate without asking", func() {
+			knownLocators.LookupReturns(fingerprint, true, nil)
+			_, err := trustingExchange(domain.ConnectionData{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(knownLocators.LookupArgsForCall(0)).To(Equal(address))
+			Expect(knownLocators.AcceptCallCount()).To(BeZero())
+		})
+
+		It("Fails loudly when the certificate has changed", func() {
+			knownLocators.LookupReturns("SHA256:00", true, nil)
+			_, err := trustingExchange(domain.ConnectionData{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("HAS CHANGED"))
+			Expect(err.Error()).To(ContainSubstring("Received fingerprint: " + fingerprint))
+			Expect(knownLocators.AcceptCallCount()).To(BeZero())
+		})
+
+		It("Does not bypass a configured CA certificate", func() {
+			otherCertificate, err := ioutil.ReadFile("../../testdata/client-cert.pem")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(caCertPath, otherCertificate, 0600)).To(Succeed())
+			knownLocators.AcceptReturns(true, nil)
+			_, err = trustingExchange(domain.ConnectionData{CACert: caCertPath})
+			Expect(err).To(HaveOccurred())
+			Expect(knownLocators.LookupCallCount()).To(BeZero())
+		})
+
+		It("Is not consulted when validation is skipped", func() {
+			_, err := trustingExchange(domain.ConnectionData{SkipSSLValidation: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(knownLocators.LookupCallCount()).To(BeZero())
+		})
+	})
+
+	Context("Client certificates", func() {
+
+		var (
+			certPath string
+			keyPath  string
+		)
+
+		BeforeEach(func() {
+			server.Close()
+			privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "client"},
+				NotBefore:             time.Now().Add(-time.Hour),
+				NotAfter:              time.Now().Add(time.Hour),
+				IsCA:                  true,
+				BasicConstraintsValid: true,
+				KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+				ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			keyDer, err := x509.MarshalPKCS8PrivateKey(privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			certPath = filepath.Join(dir, "client.pem")
+			keyPath = filepath.Join(dir, "client-key.pem")
+			Expect(ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).To(Succeed())
+			Expect(ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600)).To(Succeed())
+
+			clientCAs := x509.NewCertPool()
+			generated, err := x509.ParseCertificate(der)
+			Expect(err).NotTo(HaveOccurred())
+			clientCAs.AddCert(generated)
+			bundled, err := ioutil.ReadFile("../../testdata/client-cert.pem")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clientCAs.AppendCertsFromPEM(bundled)).To(BeTrue())
+
+			server = httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				_, _ = writer.Write([]byte(request.TLS.PeerCertificates[0].Subject.CommonName))
+			}))
+			server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+			server.StartTLS()
+		})
+
+		It("Is rejected without a client certificate", func() {
+			_, err := exchange(domain.ConnectionData{SkipSSLValidation: true})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Presents a PEM certificate and key", func() {
+			urlResponse, err := exchange(domain.ConnectionData{SkipSSLValidation: true, ClientCert: certPath, ClientKey: keyPath})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(urlResponse).To(Equal("client"))
+		})
+
+		It("Presents a PEM file holding both the certificate and the key", func() {
+			certificate, _ := ioutil.ReadFile(certPath)
+			key, _ := ioutil.ReadFile(keyPath)
+			Expect(ioutil.WriteFile(certPath, append(certificate, key...), 0600)).To(Succeed())
+			urlResponse, err := exchange(domain.ConnectionData{SkipSSLValidation: true, ClientCert: certPath})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(urlResponse).To(Equal("client"))
+		})
+
+		It("Presents a PKCS#12 certificate decrypted with the passphrase", func() {
+			connectionData := domain.ConnectionData{SkipSSLValidation: true, ClientCert: "../../testdata/client.p12", ClientCertPassphrase: "secret"}
+			urlResponse, err := exchange(connectionData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(urlResponse).To(Equal("gemfire-client"))
+		})
+
+		It("Reports a wrong PKCS#12 passphrase", func() {
+			connectionData := domain.ConnectionData{SkipSSLValidation: true, ClientCert: "../../testdata/client.p12", ClientCertPassphrase: "wrong"}
+			_, err := exchange(connectionData)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unable to decode the PKCS#12 client certificate"))
+		})
+
+		It("Reports a key without a certificate", func() {
+			_, err := exchange(domain.ConnectionData{ClientKey: keyPath})
+			Expect(err).To(MatchError("A client key requires a client certificate"))
+		})
+	})
+
+	Context("ApplyTLSOptions", func() {
+		AfterEach(func() {
+			os.Unsetenv("GEODE_CA_CERT")
+			os.Unsetenv("GEODE_CLIENT_CERT")
+			os.Unsetenv("GEODE_CLIENT_KEY")
+			os.Unsetenv("GEODE_CLIENT_CERT_PASSPHRASE")
+		})
+
+		It("Verifies certificates unless an option is given", func() {
+			ApplyTLSOptions(&commandData)
+			Expect(commandData.ConnnectionData.SkipSSLValidation).To(BeFalse())
+			Expect(commandData.ConnnectionData.CACert).To(Equal(""))
+		})
+
+		It("Uses the options given", func() {
+			commandData.UserCommand.Parameters["--ca-cert"] = caCertPath
+			commandData.UserCommand.Parameters["--skip-ssl-validation"] = ""
+			ApplyTLSOptions(&commandData)
+			Expect(commandData.ConnnectionData.CACert).To(Equal(caCertPath))
+			Expect(commandData.ConnnectionData.SkipSSLValidation).To(BeTrue())
+		})
+
+		It("Falls back to the environment for the CA certificate", func() {
+			os.Setenv("GEODE_CA_CERT", caCertPath)
+			ApplyTLSOptions(&commandData)
+			Expect(commandData.ConnnectionData.CACert).To(Equal(caCertPath))
+
+			commandData.UserCommand.Parameters["--ca-cert"] = "other.pem"
+			ApplyTLSOptions(&commandData)
+			Expect(commandData.ConnnectionData.CACert).To(Equal("other.pem"))
+		})
+
+		It("Uses the client certificate options or environment variables", func() {
+			os.Setenv("GEODE_CLIENT_CERT", "client.p12")
+			os.Setenv("GEODE_CLIENT_CERT_PASSPHRASE", "secret")
+			commandData.UserCommand.Parameters["--client-key"] = "client-key.pem"
+			ApplyTLSOptions(&commandData)
+			Expect(commandData.ConnnectionData.ClientCert).To(Equal("client.p12"))
+			Expect(commandData.ConnnectionData.ClientKey).To(Equal("client-key.pem"))
+			Expect(commandData.ConnnectionData.ClientCertPassphrase).To(Equal("secret"))
+		})
+	})
+})
@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package trust
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vito/go-interact/interact/terminal"
+)
+
+type knownLocators struct {
+	path   string
+	input  *bufio.Reader
+	output io.Writer
+}
+
+// New provides a constructor for the file based implementation of the common.KnownLocators
+// interface. The user is asked on input whether to trust a locator; with no input new locators
+// are not trusted
+func New(path string, input io.Reader, output io.Writer) (*knownLocators, error) {
+	if path == "" {
+		return nil, errors.New("the known locators file must be specified")
+	}
+	kl := &knownLocators{path: path, output: output}
+	if input != nil {
+		kl.input = bufio.NewReader(input)
+	}
+	return kl, nil
+}
+
+// FromEnvironment constructs the known locators stored in the file named by the
+// 'GEODE_KNOWN_LOCATORS' environment variable, or '~/.gemfire/known_locators'. The user is
+// only asked to trust new locators when standard input is a terminal
+func FromEnvironment() (*knownLocators, error) {
+	path := os.Getenv("GEODE_KNOWN_LOCATORS")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".gemfire", "known_locators")
+	}
+	var input io.Reader
+	if terminal.IsTerminal(int(os.Stdin.Fd())) {
+		input = os.Stdin
+	}
+	return New(path, input, os.Stderr)
+}
+
+// Lookup provides the fingerprint recorded for a locator address
+func (kl *knownLocators) Lookup(address string) (fingerprint string, known bool, err error) {
+	content, err := ioutil.ReadFile(kl.path)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == address {
+			return fields[1], true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// Accept asks the user whether to trust a locator contacted for the first time and appends its
+// fingerprint to the file when the answer is yes
+func (kl *knownLocators) Accept(address string, fingerprint string) (bool, error) {
+	if kl.input == nil {
+		return false, nil
+	}
+	fmt.Fprintf(kl.output, "The authenticity of locator %s can't be established.\n", address)
+	fmt.Fprintf(kl.output, "Certificate fingerprint is %s.\n", fingerprint)
+	fmt.Fprint(kl.output, "Are you sure you want to trust it (yes/no)? ")
+	answer, err := kl.input.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	if strings.ToLower(strings.TrimSpace(answer)) != "yes" {
+		return false, nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(kl.path), 0700)
+	if err != nil {
+		return false, err
+	}
+	file, err := os.OpenFile(kl.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return false, err
+	}
+	_, err = fmt.Fprintf(file, "%s %s\n", address, fingerprint)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprintf(kl.output, "Added %s to the known locators in %s.\n", address, kl.path)
+	return true, nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package trust_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/trust"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KnownLocators", func() {
+
+	const fingerprint = "SHA256:AB:CD"
+
+	var (
+		dir    string
+		path   string
+		output *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "trust")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, ".gemfire", "known_locators")
+		output = &bytes.Buffer{}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("Requires a file", func() {
+		_, err := New("", nil, output)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Does not know any locator before the file exists", func() {
+		knownLocators, _ := New(path, nil, output)
+		_, known, err := knownLocators.Lookup("locator:7070")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(known).To(BeFalse())
+	})
+
+	It("Records the fingerprint when the user trusts the locator", func() {
+		knownLocators, _ := New(path, strings.NewReader("yes\n"), output)
+		accepted, err := knownLocators.Accept("locator:7070", fingerprint)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(accepted).To(BeTrue())
+		Expect(output.String()).To(ContainSubstring("Certificate fingerprint is SHA256:AB:CD."))
+
+		recorded, known, err := knownLocators.Lookup("locator:7070")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(known).To(BeTrue())
+		Expect(recorded).To(Equal(fingerprint))
+
+		_, known, _ = knownLocators.Lookup("locator:8080")
+		Expect(known).To(BeFalse())
+
+		info, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("Records nothing when the user does not trust the locator", func() {
+		knownLocators, _ := New(path, strings.NewReader("no\n"), output)
+		accepted, err := knownLocators.Accept("locator:7070", fingerprint)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(accepted).To(BeFalse())
+		_, known, _ := knownLocators.Lookup("locator:7070")
+		Expect(known).To(BeFalse())
+	})
+
+	It("Does not trust new locators without input", func() {
+		knownLocators, _ := New(path, nil, output)
+		accepted, err := knownLocators.Accept("locator:7070", fingerprint)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(accepted).To(BeFalse())
+		Expect(output.String()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package gemfire
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"code.cloudfoundry.org/cli/cf/errors"
+	"code.cloudfoundry.org/cli/plugin"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+)
+
+// ephemeralKeyPrefix starts the names of the service keys created with the '--ephemeral-key' option
+const ephemeralKeyPrefix = "gemfire-ephemeral-"
+
+// ephemeralKey is a service key created for a single invocation of the plugin
+type ephemeralKey struct {
+	cliConnection plugin.CliConnection
+	target        string
+	name          string
+	once          sync.Once
+	err           error
+}
+
+// createEphemeralKey creates a uniquely named service key for the target and selects it as the
+// service key of the command
+func createEphemeralKey(cliConnection plugin.CliConnection, commandData *domain.CommandData) (*ephemeralKey, error) {
+	if common.HasOption(commandData.UserCommand.Parameters, []string{"--service-key"}) {
+		return nil, errors.New("Use either --ephemeral-key or --service-key")
+	}
+	location, err := parseInstanceLocation(commandData)
+	if err != nil {
+		return nil, err
+	}
+	// the cf CLI creates service keys in the targeted space
+	if !location.inTargetedSpace() {
+		return nil, errors.New("--ephemeral-key requires the service instance to be in the targeted space")
+	}
+	suffix := make([]byte, 8)
+	_, err = rand.Read(suffix)
+	if err != nil {
+		return nil, err
+	}
+	key := &ephemeralKey{cliConnection: cliConnection, target: commandData.Target, name: ephemeralKeyPrefix + hex.EncodeToString(suffix)}
+	_, err = cliConnection.CliCommandWithoutTerminalOutput("create-service-key", key.target, key.name)
+	if err != nil {
+		// the key may have been created even though the command failed
+		key.Delete()
+		return nil, errors.New("Unable to create a service key for " + key.target + ". Error: " + err.Error())
+	}
+	commandData.UserCommand.Parameters["--service-key"] = key.name
+	return key, nil
+}
+
+// Delete removes the service key. It is only attempted once and reports on stderr when the key has
+// to be deleted by hand
+func (k *ephemeralKey) Delete() error {
+	k.once.Do(func() {
+		_, k.err = k.cliConnection.CliCommandWithoutTerminalOutput("delete-service-key", "-f", k.target, k.name)
+		if k.err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to delete the service key %s, delete it with 'cf delete-service-key %s %s'. Error: %s\n",
+				k.name, k.target, k.name, k.err.Error())
+		}
+	})
+	return k.err
+}
+
+// deleteOnInterrupt deletes the service key when the plugin is interrupted before it completes.
+// The returned function stops watching for interrupts
+func (k *ephemeralKey) deleteOnInterrupt() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			k.Delete()
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package gemfire
+
+import (
+	"encoding/json"
+	"strings"
+
+	"code.cloudfoundry.org/cli/plugin"
+	plugin_models "code.cloudfoundry.org/cli/plugin/models"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/format"
+)
+
+// gemfireServiceNames identify the service offerings of GemFire, e.g. p-cloudcache and p.gemfire
+var gemfireServiceNames = []string{"cloudcache", "gemfire"}
+
+// instance describes a GemFire service instance in the targeted space
+type instance struct {
+	Name          string `json:"name"`
+	Service       string `json:"service"`
+	Plan          string `json:"plan"`
+	LastOperation string `json:"last_operation"`
+	ServiceKey    string `json:"service_key"`
+}
+
+// ListInstances renders the GemFire service instances of the targeted org and space, and whether
+// each of them has a service key to connect with
+func ListInstances(cliConnection plugin.CliConnection, userCommand domain.UserCommand) (string, error) {
+	services, err := cliConnection.GetServices()
+	if err != nil {
+		return "", err
+	}
+	pc := &pluginConnection{cliConnection: cliConnection}
+	instances := []instance{}
+	for _, service := range services {
+		if !isGemFireService(service) {
+			continue
+		}
+		instances = append(instances, instance{
+			Name:          service.Name,
+			Service:       service.Service.Name,
+			Plan:          service.ServicePlan.Name,
+			LastOperation: strings.TrimSpace(service.LastOperation.Type + " " + service.LastOperation.State),
+			ServiceKey:    pc.describeServiceKey(service, userCommand),
+		})
+	}
+	if len(instances) == 0 {
+		return "No GemFire service instances found in the targeted space", nil
+	}
+	content, err := json.Marshal(instances)
+	if err != nil {
+		return "", err
+	}
+	return format.Render(string(content), common.GetOption(userCommand.Parameters, []string{"--output", "-o"}))
+}
+
+func isGemFireService(service plugin_models.GetServices_Model) bool {
+	if service.IsUserProvided {
+		return false
+	}
+	for _, name := range gemfireServiceNames {
+		if strings.Contains(service.Service.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeServiceKey reports whether the service instance has the service key selected as for any
+// other command. Only the service keys are listed, their credentials are not read
+func (pc *pluginConnection) describeServiceKey(service plugin_models.GetServices_Model, userCommand domain.UserCommand) string {
+	serviceKeys, _, err := pc.listServiceKeyBindings(service.Guid)
+	if err == errNoCredentialBindings {
+		serviceKeys, err = pc.listServiceKeys(service.Name)
+	}
+	if err != nil {
+		return "no"
+	}
+	_, err = selectServiceKey(&domain.CommandData{Target: service.Name, UserCommand: userCommand}, serviceKeys)
+	if err != nil {
+		return "no"
+	}
+	return "yes"
+}
@@ -0,0 +1,120 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package gemfire_test
+
+import (
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/cli/plugin/models"
+	"code.cloudfoundry.org/cli/plugin/pluginfakes"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/gemfire"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListInstances", func() {
+
+	var (
+		cliConnection *pluginfakes.FakeCliConnection
+		userCommand   domain.UserCommand
+		commands      []string
+	)
+
+	service := func(name string, offering string, state string) plugin_models.GetServices_Model {
+		model := plugin_models.GetServices_Model{Guid: name + "-guid", Name: name}
+		model.Service.Name = offering
+		model.ServicePlan.Name = "small"
+		model.LastOperation.Type = "create"
+		model.LastOperation.State = state
+		return model
+	}
+
+	BeforeEach(func() {
+		cliConnection = new(pluginfakes.FakeCliConnection)
+		userCommand = domain.UserCommand{Command: "instances", Parameters: map[string]string{"-o": "json"}}
+		commands = nil
+		cliConnection.GetServicesReturns([]plugin_models.GetServices_Model{
+			service("pcc1", "p-cloudcache", "succeeded"),
+			service("mysql1", "p.mysql", "succeeded"),
+			service("pcc2", "p-cloudcache", "in progress"),
+		}, nil)
+		cliConnection.CliCommandWithoutTerminalOutputStub = func(args ...string) ([]string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			switch strings.Join(args, " ") {
+			case "curl /v3/service_credential_bindings?type=key&per_page=5000&service_instance_guids=pcc1-guid":
+				return []string{`{"resources": [{"guid": "key1-guid", "name": "key1"}]}`}, nil
+			case "curl /v3/service_credential_bindings?type=key&per_page=5000&service_instance_guids=pcc2-guid":
+				return []string{`{"resources": []}`}, nil
+			}
+			return nil, errors.New("unexpected command")
+		}
+	})
+
+	It("Lists the GemFire service instances with their plan, last operation and service key", func() {
+		output, err := ListInstances(cliConnection, userCommand)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`[
+			{"name": "pcc1", "service": "p-cloudcache", "plan": "small", "last_operation": "create succeeded", "service_key": "yes"},
+			{"name": "pcc2", "service": "p-cloudcache", "plan": "small", "last_operation": "create in progress", "service_key": "no"}
+		]`))
+		Expect(strings.Join(commands, "\n")).NotTo(ContainSubstring("details"))
+	})
+
+	It("Checks the service key given on the command line", func() {
+		userCommand.Parameters["--service-key"] = "other-key"
+		output, err := ListInstances(cliConnection, userCommand)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).NotTo(ContainSubstring(`"service_key":"yes"`))
+	})
+
+	It("Lists the service keys with the cf CLI when the API does not know credential bindings", func() {
+		cliConnection.CliCommandWithoutTerminalOutputStub = func(args ...string) ([]string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			switch strings.Join(args, " ") {
+			case "service-keys pcc1":
+				return []string{"Getting keys", "", "name", "key1"}, nil
+			case "service-keys pcc2":
+				return []string{"Getting keys", "No service key for service instance pcc2"}, nil
+			}
+			if args[0] == "curl" {
+				return []string{`{"code": 10000, "description": "Unknown request"}`}, nil
+			}
+			return nil, errors.New("unexpected command")
+		}
+		output, err := ListInstances(cliConnection, userCommand)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`[
+			{"name": "pcc1", "service": "p-cloudcache", "plan": "small", "last_operation": "create succeeded", "service_key": "yes"},
+			{"name": "pcc2", "service": "p-cloudcache", "plan": "small", "last_operation": "create in progress", "service_key": "no"}
+		]`))
+		Expect(commands).NotTo(ContainElement(HavePrefix("service-key ")))
+	})
+
+	It("Reports a space without GemFire service instances", func() {
+		cliConnection.GetServicesReturns(nil, nil)
+		output, err := ListInstances(cliConnection, userCommand)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("No GemFire service instances found in the targeted space"))
+	})
+
+	It("Returns the error of the cf CLI", func() {
+		cliConnection.GetServicesReturns(nil, errors.New("not logged in"))
+		_, err := ListInstances(cliConnection, userCommand)
+		Expect(err).To(MatchError("not logged in"))
+	})
+})
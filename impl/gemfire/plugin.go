@@ -24,7 +24,9 @@ import (
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/clusterconfig"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/format"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/shell"
 )
 
 // BasicPlugin declares the dataset that commands work on
@@ -48,28 +50,82 @@ func (c *BasicPlugin) Run(cliConnection plugin.CliConnection, args []string) {
 		return
 	}
 	var err error
+	// listing the service instances needs no target
+	if len(args) > 1 && args[1] == "instances" {
+		output, err := ListInstances(cliConnection, common.ParseUserCommand(args[1:]))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(common.ExitCode(err))
+		}
+		fmt.Println(output)
+		return
+	}
+	// commands working on two clusters connect to both service instances named
+	if len(args) > 1 && common.Contains(clusterconfig.TwoClusterCommands, args[1]) {
+		pluginConnection, err := New(cliConnection)
+		if err == nil {
+			err = clusterconfig.RunTwoClusterCommand(c.comm, pluginConnection.GetConnectionData, common.ParseUserCommand(args[1:]))
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(common.ExitCode(err))
+		}
+		return
+	}
 	c.commandData.Target, c.commandData.UserCommand = common.GetTargetAndClusterCommand(args)
 	if c.commandData.UserCommand.Command == "" {
 		fmt.Println("missing command")
 		os.Exit(1)
 	}
 
+	// an ephemeral key is deleted however the command ends
+	exit := os.Exit
+	if common.HasOption(c.commandData.UserCommand.Parameters, []string{"--ephemeral-key"}) {
+		key, err := createEphemeralKey(cliConnection, &c.commandData)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(common.ExitCode(err))
+		}
+		stop := key.deleteOnInterrupt()
+		defer func() {
+			stop()
+			key.Delete()
+		}()
+		exit = func(code int) {
+			stop()
+			key.Delete()
+			os.Exit(code)
+		}
+	}
+
 	pluginConnection, err := New(cliConnection)
 	if err != nil {
 		fmt.Printf(format.GenericErrorMessage, err.Error())
-		os.Exit(1)
+		exit(1)
 	}
 	err = pluginConnection.GetConnectionData(&c.commandData)
 	if err != nil {
 		fmt.Printf(format.GenericErrorMessage, err.Error())
-		os.Exit(1)
+		exit(1)
+	}
+
+	if c.commandData.UserCommand.Command == "shell" {
+		interactive, err := shell.New(c.comm, os.Stdin, os.Stdout)
+		if err == nil {
+			err = interactive.Run(&c.commandData)
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			exit(common.ExitCode(err))
+		}
+		return
 	}
 
 	// From this point common code can handle the processing of the command
 	err = c.comm.ProcessCommand(&c.commandData)
 	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(1)
+		exit(common.ExitCode(err))
 	}
 
 	return
@@ -91,11 +147,21 @@ func (c *BasicPlugin) GetMetadata() plugin.PluginMetadata {
 				HelpText: "Commands to interact with Geode cluster.\n",
 				UsageDetails: plugin.Usage{
 					Usage: "cf  gemfire  [target]  <command>  [options] \n\n" +
-						"\ttarget:\n\t\ta pcc_instance. \n" +
+						"\ttarget:\n\t\ta pcc_instance, or <org>/<space>/<pcc_instance> outside of the targeted space. \n" +
 						"\t\tomit if 'GEODE_TARGET' environment variable is set \n" +
 						"\tcommand:\n\t\tuse 'cf gemfire <target> commands' to see a list of supported commands \n" +
+						"\t\tuse 'cf gemfire <target> plan -f <manifest>' to compare the cluster with a manifest, and apply -f <manifest> [--yes] to make the changes \n" +
+						"\t\tuse 'cf gemfire <target> export-config [--dir <directory>]' to export the configuration of the cluster as manifests \n" +
+						"\t\tuse 'cf gemfire diff <pcc_instance> <pcc_instance>' to compare the configuration of two clusters \n" +
+						"\t\tuse 'cf gemfire copy <region|index|disk-store|gateway-receiver> --id <id> --from <pcc_instance> --to <pcc_instance>' to create an entity of one cluster on another, --all copies every entity \n" +
+						"\t\tuse 'cf gemfire instances' to list the GemFire service instances of the targeted space \n" +
+						"\t\tuse 'cf gemfire <target> shell' to start an interactive session \n" +
 						"\toptions:\n\t\tuse 'cf gemfire <target> command -help' to see options for individual command." +
 						format.GeneralOptions + "\n" +
+						"\t\t--service-key <name> selects the service key of the instance, by default the first one\n" +
+						"\t\t--role <cluster_operator|developer|readonly> selects the user of the service key, by default cluster_operator\n" +
+						"\t\t--org <org> --space <space> select the space of the instance, by default the targeted one\n" +
+						"\t\t--ephemeral-key creates a service key for the command and deletes it afterwards\n" +
 						"\thelp\nt\t\t: use -h or --help for general help, and provide <command> -help for command specific help",
 				},
 			},
@@ -43,73 +43,162 @@ func New(connection plugin.CliConnection) (impl.ConnectionProvider, error) {
 // GetConnectionData provides the connection data from a PCC cluster using the CF CLI
 func (pc *pluginConnection) GetConnectionData(commandData *domain.CommandData) error {
 	commandData.ConnnectionData = domain.ConnectionData{}
-	serviceKey, err := pc.getServiceKey(commandData.Target)
+	serviceKey, err := pc.getServiceKey(commandData)
 	if err != nil {
 		return err
 	}
 
-	return pc.getServiceKeyDetails(commandData, serviceKey)
+	err = pc.applyServiceKey(commandData, serviceKey)
+	if err != nil {
+		return err
+	}
+
+	// certificates are verified unless the cf CLI skips validation, e.g. after 'cf api --skip-ssl-validation'
+	common.ApplyTLSOptions(commandData)
+	sslDisabled, err := pc.cliConnection.IsSSLDisabled()
+	if err != nil {
+		return err
+	}
+	commandData.ConnnectionData.SkipSSLValidation = commandData.ConnnectionData.SkipSSLValidation || sslDisabled
 
+	cfToken := commandData.ConnnectionData.Token
+	err = common.ApplyAuthOptions(commandData)
+	if err != nil {
+		return err
+	}
+	// only the access token of the cf CLI can be renewed
+	if cfToken != "" && commandData.ConnnectionData.Token == cfToken {
+		commandData.ConnnectionData.TokenRenewer = &cfTokenRenewer{
+			cliConnection:  pc.cliConnection,
+			connectionData: &commandData.ConnnectionData,
+		}
+	}
+	return nil
 }
 
-func (pc *pluginConnection) getServiceKey(target string) (serviceKey string, err error) {
-	results, err := pc.cliConnection.CliCommandWithoutTerminalOutput("service-keys", target)
+// cfTokenRenewer re-acquires the access token through the cf CLI, which refreshes it when it has
+// expired, and keeps it for the requests that follow
+type cfTokenRenewer struct {
+	cliConnection  plugin.CliConnection
+	connectionData *domain.ConnectionData
+}
+
+// RenewToken implements the domain.TokenRenewer interface
+func (r *cfTokenRenewer) RenewToken() (string, error) {
+	token, err := r.cliConnection.AccessToken()
+	if err == nil && token == "" {
+		err = errors.New("no access token")
+	}
 	if err != nil {
-		return "", err
+		return "", common.NewAuthError("The access token of the cf CLI has expired and could not be refreshed, run 'cf login' and try again. Error: " + err.Error())
 	}
-	hasKey := false
-	if strings.Contains(results[1], "No service key for service instance") {
+	r.connectionData.Token = token
+	return token, nil
+}
+
+// ServiceKeyRoles lists the values accepted by the '--role' option
+var ServiceKeyRoles = []string{"cluster_operator", "developer", "readonly"}
+
+// getServiceKey provides the key named with the '--service-key' option, by default the first key
+// of the service instance, which is in the targeted space unless another org or space is given.
+// It is read through the CF v3 API, falling back to the output of the cf CLI when the API does not
+// provide service credential bindings
+func (pc *pluginConnection) getServiceKey(commandData *domain.CommandData) (domain.ServiceKey, error) {
+	location, err := parseInstanceLocation(commandData)
+	if err != nil {
+		return domain.ServiceKey{}, err
+	}
+	serviceKey, err := pc.getServiceKeyFromAPI(commandData, location)
+	if err != errNoCredentialBindings {
+		return serviceKey, err
+	}
+	// the cf CLI only lists the service keys of the targeted space
+	if !location.inTargetedSpace() {
+		return domain.ServiceKey{}, errors.New("Service instances in other orgs and spaces require the v3 API of Cloud Foundry, run 'cf target' to select the space instead")
+	}
+	serviceKeys, err := pc.listServiceKeys(commandData.Target)
+	if err != nil {
+		return domain.ServiceKey{}, err
+	}
+	name, err := selectServiceKey(commandData, serviceKeys)
+	if err != nil {
+		return domain.ServiceKey{}, err
+	}
+	return pc.getServiceKeyDetails(commandData.Target, name)
+}
+
+// selectServiceKey picks the key named with the '--service-key' option, by default the first one
+func selectServiceKey(commandData *domain.CommandData, serviceKeys []string) (string, error) {
+	target := commandData.Target
+	if len(serviceKeys) == 0 {
 		return "", fmt.Errorf(format.NoServiceKeyMessage, target, target)
 	}
+	serviceKey := common.GetOption(commandData.UserCommand.Parameters, []string{"--service-key"})
+	if serviceKey == "" {
+		return serviceKeys[0], nil
+	}
+	if !common.Contains(serviceKeys, serviceKey) {
+		return "", errors.New("Service key " + serviceKey + " not found for " + target +
+			". Available service keys: " + strings.Join(serviceKeys, ", "))
+	}
+	return serviceKey, nil
+}
+
+func (pc *pluginConnection) listServiceKeys(target string) (serviceKeys []string, err error) {
+	results, err := pc.cliConnection.CliCommandWithoutTerminalOutput("service-keys", target)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) > 1 && strings.Contains(results[1], "No service key for service instance") {
+		return nil, nil
+	}
+	hasKey := false
 	for _, value := range results {
 		line := strings.Fields(value)
 		if len(line) > 0 {
 			if hasKey {
-				serviceKey = line[0]
-				return
+				serviceKeys = append(serviceKeys, line[0])
 			} else if line[0] == "name" {
 				hasKey = true
 			}
 		}
 	}
-	if serviceKey == "" {
-		err = fmt.Errorf(format.NoServiceKeyMessage, target, target)
-	}
 	return
 }
 
-func (pc *pluginConnection) getServiceKeyDetails(commandData *domain.CommandData, serviceKey string) (err error) {
-	keyInfo, err := pc.cliConnection.CliCommandWithoutTerminalOutput("service-key", commandData.Target, serviceKey)
+func (pc *pluginConnection) getServiceKeyDetails(target string, serviceKey string) (serviceKeyStruct domain.ServiceKey, err error) {
+	keyInfo, err := pc.cliConnection.CliCommandWithoutTerminalOutput("service-key", target, serviceKey)
 	if err != nil {
-		return err
+		return
 	}
 
 	if len(keyInfo) < 2 {
-		return errors.New(format.InvalidServiceKeyResponse)
+		return serviceKeyStruct, errors.New(format.InvalidServiceKeyResponse)
 	}
 	keyInfo = keyInfo[2:] //take out first two lines of cf service-key ... output
 	joinKeyInfo := strings.Join(keyInfo, "\n")
-	serviceKeyStruct := domain.ServiceKey{}
 
 	err = json.Unmarshal([]byte(joinKeyInfo), &serviceKeyStruct)
-	if err != nil {
-		return err
-	}
+	return
+}
 
+// applyServiceKey sets the locator address and credentials of the service key
+func (pc *pluginConnection) applyServiceKey(commandData *domain.CommandData, serviceKeyStruct domain.ServiceKey) (err error) {
 	commandData.ConnnectionData.LocatorAddress = strings.TrimSuffix(serviceKeyStruct.Urls.Gfsh, "/gemfire/v1")
 
-	// use the credentials in the command line
-	if commandData.ConnnectionData.Username == "" || commandData.ConnnectionData.Password == "" {
-		commandData.ConnnectionData.Username = common.GetOption(commandData.UserCommand.Parameters, []string{"--user", "-u"})
-		commandData.ConnnectionData.Password = common.GetOption(commandData.UserCommand.Parameters, []string{"--password", "-p"})
-	}
-
-	// find credentials in the service key
-	for _, user := range serviceKeyStruct.Users {
-		if strings.HasPrefix(user.Username, "cluster_operator") {
-			commandData.ConnnectionData.Username = user.Username
-			commandData.ConnnectionData.Password = user.Password
+	// the credentials in the command line take precedence over the service key
+	username := common.GetOption(commandData.UserCommand.Parameters, []string{"--user", "-u"})
+	password := common.GetOption(commandData.UserCommand.Parameters, []string{"--password", "-p"})
+	if username != "" && password != "" {
+		commandData.ConnnectionData.Username = username
+		commandData.ConnnectionData.Password = password
+	} else {
+		user, err := selectServiceKeyUser(serviceKeyStruct, username, common.GetOption(commandData.UserCommand.Parameters, []string{"--role"}))
+		if err != nil {
+			return err
 		}
+		commandData.ConnnectionData.Username = user.Username
+		commandData.ConnnectionData.Password = user.Password
 	}
 
 	// find the access token if any
@@ -118,3 +207,36 @@ func (pc *pluginConnection) getServiceKeyDetails(commandData *domain.CommandData
 
 	return
 }
+
+// selectServiceKeyUser finds the user of the service key with a username given on the command line,
+// or otherwise with a role. Without a cluster_operator user, the default, no credentials are used
+func selectServiceKeyUser(serviceKey domain.ServiceKey, username string, role string) (domain.ServiceKeyUsers, error) {
+	if username != "" {
+		for _, user := range serviceKey.Users {
+			if user.Username == username {
+				return user, nil
+			}
+		}
+		return domain.ServiceKeyUsers{}, errors.New("User " + username + " not found in the service key, provide the password with -p")
+	}
+
+	explicitRole := role != ""
+	if !explicitRole {
+		role = ServiceKeyRoles[0]
+	} else if !common.Contains(ServiceKeyRoles, role) {
+		return domain.ServiceKeyUsers{}, errors.New("Invalid role: " + role + ", use one of: " + strings.Join(ServiceKeyRoles, ", "))
+	}
+	var available []string
+	for _, user := range serviceKey.Users {
+		// older service keys name the role only in the username
+		if common.Contains(user.Roles, role) || (len(user.Roles) == 0 && strings.HasPrefix(user.Username, role)) {
+			return user, nil
+		}
+		available = append(available, user.Roles...)
+	}
+	if !explicitRole {
+		return domain.ServiceKeyUsers{}, nil
+	}
+	return domain.ServiceKeyUsers{}, errors.New("The service key has no user with the role " + role +
+		". Available roles: " + strings.Join(available, ", "))
+}
@@ -16,6 +16,7 @@
 package gemfire_test
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -24,8 +25,10 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"code.cloudfoundry.org/cli/plugin/models"
 	"code.cloudfoundry.org/cli/plugin/pluginfakes"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/format"
 	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/gemfire"
 )
@@ -51,8 +54,24 @@ var _ = Describe("PluginConnection", func() {
 		commandData.Target = "pcc1"
 	})
 
-	Context("We have a service and a service-key", func() {
+	// respond provides the output of cf commands by their arguments, failing any other command
+	respond := func(responses map[string][]string) {
+		cliConnection.CliCommandWithoutTerminalOutputStub = func(args ...string) ([]string, error) {
+			response, found := responses[strings.Join(args, " ")]
+			if !found {
+				return nil, errors.New("unexpected command: cf " + strings.Join(args, " "))
+			}
+			return response, nil
+		}
+	}
+
+	// unknownRequest is the response of a CF API without the v3 endpoints
+	unknownRequest := []string{`{"code": 10000, "description": "Unknown request"}`}
+	instanceRequest := "curl /v3/service_instances?names=pcc1&space_guids=space-guid"
+
+	Context("We have a service and a service-key, and a CF API without credential bindings", func() {
 		BeforeEach(func() {
+			cliConnection.GetCurrentSpaceReturns(plugin_models.Space{SpaceFields: plugin_models.SpaceFields{Guid: "space-guid", Name: "dev"}}, nil)
 			goodServiceKeyResponseString := `Getting key pcc1ServiceKey for service instance pcc1 as admin...
 				
 {
@@ -93,29 +112,302 @@ var _ = Describe("PluginConnection", func() {
  }
 }`
 			goodServiceKeyResponse = strings.Split(goodServiceKeyResponseString, "\n")
+			respond(map[string][]string{instanceRequest: unknownRequest, "service-keys pcc1": {"name", "pcc1ServiceKey"}, "service-key pcc1 pcc1ServiceKey": goodServiceKeyResponse})
 		})
 
 		It("Returns a populated ConnectionData object", func() {
-			cliConnection.CliCommandWithoutTerminalOutputReturnsOnCall(0, []string{"name", "pcc1ServiceKey"}, nil)
-			cliConnection.CliCommandWithoutTerminalOutputReturnsOnCall(1, goodServiceKeyResponse, nil)
 			err := pluginConnection.GetConnectionData(&commandData)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(cliConnection.CliCommandWithoutTerminalOutputCallCount()).To(Equal(2))
+			Expect(cliConnection.CliCommandWithoutTerminalOutputCallCount()).To(Equal(3))
 			Expect(commandData.ConnnectionData.Username).To(Equal("cluster_operator_M5Scgeb0b6yp5f99E6SA8w"))
 			Expect(commandData.ConnnectionData.Password).To(Equal("AMmxU9H6J5KSCYDLccipIw"))
 			Expect(commandData.ConnnectionData.LocatorAddress).To(Equal("https://cloudcache-45371efd-f4ca-4549-a5f2-e06330aa53dc.sys.riverbank.cf-app.com"))
+			Expect(commandData.ConnnectionData.SkipSSLValidation).To(BeFalse())
+		})
+
+		It("Skips SSL validation when the cf CLI does", func() {
+			cliConnection.IsSSLDisabledReturns(true, nil)
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.SkipSSLValidation).To(BeTrue())
+		})
+
+		It("Uses the access token of the cf CLI", func() {
+			cliConnection.AccessTokenReturns("bearer cf-token", nil)
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Token).To(Equal("bearer cf-token"))
+		})
+
+		It("Renews the access token through the cf CLI", func() {
+			cliConnection.AccessTokenReturnsOnCall(0, "bearer cf-token", nil)
+			cliConnection.AccessTokenReturnsOnCall(1, "bearer renewed-token", nil)
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.TokenRenewer).NotTo(BeNil())
+
+			token, err := commandData.ConnnectionData.TokenRenewer.RenewToken()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("bearer renewed-token"))
+			Expect(commandData.ConnnectionData.Token).To(Equal("bearer renewed-token"))
+		})
+
+		It("Asks to log in to the cf CLI when the access token cannot be renewed", func() {
+			cliConnection.AccessTokenReturnsOnCall(0, "bearer cf-token", nil)
+			cliConnection.AccessTokenReturnsOnCall(1, "", errors.New("refresh token expired"))
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = commandData.ConnnectionData.TokenRenewer.RenewToken()
+			Expect(err).To(MatchError(ContainSubstring("run 'cf login'")))
+			Expect(common.ExitCode(err)).To(Equal(common.ExitCodeAuthFailure))
+		})
+
+		It("Does not renew tokens given as options", func() {
+			cliConnection.AccessTokenReturns("bearer cf-token", nil)
+			commandData.UserCommand.Parameters["--token"] = "other-token"
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.TokenRenewer).To(BeNil())
+		})
+
+		It("Selects the user with a role", func() {
+			commandData.UserCommand.Parameters["--role"] = "developer"
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Username).To(Equal("developer_3VTqJTIkftQX3pBJcSW1w"))
+			Expect(commandData.ConnnectionData.Password).To(Equal("PVTrpLgX7K53rthdvd67CQ"))
+		})
+
+		It("Reports roles the service key has no user for", func() {
+			commandData.UserCommand.Parameters["--role"] = "readonly"
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).To(MatchError("The service key has no user with the role readonly. Available roles: cluster_operator, developer"))
+
+			commandData.UserCommand.Parameters["--role"] = "admin"
+			err = pluginConnection.GetConnectionData(&commandData)
+			Expect(err).To(MatchError(ContainSubstring("Invalid role: admin")))
+		})
+
+		It("Prefers the credentials given on the command line", func() {
+			commandData.UserCommand.Parameters["-u"] = "admin"
+			commandData.UserCommand.Parameters["-p"] = "secret"
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Username).To(Equal("admin"))
+			Expect(commandData.ConnnectionData.Password).To(Equal("secret"))
+		})
+
+		It("Uses the password of the service key user given on the command line", func() {
+			commandData.UserCommand.Parameters["-u"] = "developer_3VTqJTIkftQX3pBJcSW1w"
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Password).To(Equal("PVTrpLgX7K53rthdvd67CQ"))
+		})
+
+		It("Uses the service key given on the command line", func() {
+			respond(map[string][]string{instanceRequest: unknownRequest, "service-keys pcc1": {"name", "pcc1ServiceKey", "pcc1OtherKey"}, "service-key pcc1 pcc1OtherKey": goodServiceKeyResponse})
+			commandData.UserCommand.Parameters["--service-key"] = "pcc1OtherKey"
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cliConnection.CliCommandWithoutTerminalOutputArgsForCall(2)).To(Equal([]string{"service-key", "pcc1", "pcc1OtherKey"}))
+		})
+
+		It("Lists the service keys when the one given does not exist", func() {
+			respond(map[string][]string{instanceRequest: unknownRequest, "service-keys pcc1": {"name", "pcc1ServiceKey", "pcc1OtherKey"}})
+			commandData.UserCommand.Parameters["--service-key"] = "missing"
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).To(MatchError("Service key missing not found for pcc1. Available service keys: pcc1ServiceKey, pcc1OtherKey"))
+			Expect(cliConnection.CliCommandWithoutTerminalOutputCallCount()).To(Equal(2))
+		})
+
+		It("Uses the CA certificate option", func() {
+			commandData.UserCommand.Parameters["--ca-cert"] = "ca.pem"
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.CACert).To(Equal("ca.pem"))
+			Expect(commandData.ConnnectionData.SkipSSLValidation).To(BeFalse())
+		})
+	})
+
+	Context("The CF API provides service credential bindings", func() {
+
+		var responses map[string][]string
+
+		credentials := func(username string) []string {
+			return strings.Split(`{
+  "credentials": {
+    "urls": {
+      "gfsh": "https://cloudcache-1.sys.example.com/gemfire/v1"
+    },
+    "users": [
+      {
+        "password": "secret",
+        "roles": ["cluster_operator"],
+        "username": "`+username+`"
+      }
+    ]
+  }
+}`, "\n")
+		}
+
+		BeforeEach(func() {
+			cliConnection.GetCurrentSpaceReturns(plugin_models.Space{SpaceFields: plugin_models.SpaceFields{Guid: "space-guid", Name: "dev"}}, nil)
+			responses = map[string][]string{
+				"curl /v3/service_instances?names=pcc1&space_guids=space-guid":                                     {`{"resources": [{"guid": "instance-guid", "name": "pcc1"}]}`},
+				"curl /v3/service_credential_bindings?type=key&per_page=5000&service_instance_guids=instance-guid": {`{"resources": [{"guid": "key1-guid", "name": "key1"}, {"guid": "key2-guid", "name": "key2"}]}`},
+				"curl /v3/service_credential_bindings/key1-guid/details":                                           credentials("cluster_operator_key1"),
+				"curl /v3/service_credential_bindings/key2-guid/details":                                           credentials("cluster_operator_key2"),
+			}
+			respond(responses)
+		})
+
+		It("Reads the first service key through the API", func() {
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Username).To(Equal("cluster_operator_key1"))
+			Expect(commandData.ConnnectionData.Password).To(Equal("secret"))
+			Expect(commandData.ConnnectionData.LocatorAddress).To(Equal("https://cloudcache-1.sys.example.com"))
+			Expect(cliConnection.CliCommandWithoutTerminalOutputCallCount()).To(Equal(3))
+		})
+
+		It("Reads the service key given on the command line", func() {
+			commandData.UserCommand.Parameters["--service-key"] = "key2"
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Username).To(Equal("cluster_operator_key2"))
+		})
+
+		It("Lists the service keys when the one given does not exist", func() {
+			commandData.UserCommand.Parameters["--service-key"] = "missing"
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).To(MatchError("Service key missing not found for pcc1. Available service keys: key1, key2"))
+		})
+
+		It("Reports a service instance that does not exist in the space", func() {
+			responses["curl /v3/service_instances?names=pcc1&space_guids=space-guid"] = []string{`{"resources": []}`}
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).To(MatchError("Service instance pcc1 not found"))
+		})
+
+		It("Reports a service instance without service keys", func() {
+			responses["curl /v3/service_credential_bindings?type=key&per_page=5000&service_instance_guids=instance-guid"] = []string{`{"resources": []}`}
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).To(MatchError(fmt.Sprintf(format.NoServiceKeyMessage, "pcc1", "pcc1")))
+		})
+
+		It("Reports the errors of the API", func() {
+			responses["curl /v3/service_credential_bindings/key1-guid/details"] = []string{`{"errors": [{"code": 10003, "title": "CF-NotAuthorized", "detail": "You are not authorized to perform the requested action"}]}`}
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).To(MatchError(ContainSubstring("CF-NotAuthorized: You are not authorized")))
+		})
+
+		It("Falls back to the output of the cf CLI when the API does not know credential bindings", func() {
+			responses["curl /v3/service_credential_bindings?type=key&per_page=5000&service_instance_guids=instance-guid"] = []string{`{"errors": [{"code": 10000, "title": "CF-NotFound", "detail": "Unknown request"}]}`}
+			responses["service-keys pcc1"] = []string{"name", "key1"}
+			responses["service-key pcc1 key1"] = append([]string{"Getting key key1 for service instance pcc1 as admin...", ""}, `{"urls": {"gfsh": "https://cloudcache-1.sys.example.com/gemfire/v1"}, "users": [{"password": "secret", "roles": ["cluster_operator"], "username": "cluster_operator_text"}]}`)
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Username).To(Equal("cluster_operator_text"))
+		})
+
+		It("Reports the failures of cf curl instead of falling back", func() {
+			delete(responses, instanceRequest)
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).To(MatchError("unexpected command: cf " + instanceRequest))
+			Expect(cliConnection.CliCommandWithoutTerminalOutputCallCount()).To(Equal(1))
+		})
+
+		It("Requires a targeted space", func() {
+			cliConnection.GetCurrentSpaceReturns(plugin_models.Space{}, nil)
+			err := pluginConnection.GetConnectionData(&commandData)
+			Expect(err).To(MatchError(ContainSubstring("No space targeted")))
+			Expect(cliConnection.CliCommandWithoutTerminalOutputCallCount()).To(BeZero())
+		})
+
+		Context("The service instance is in another org and space", func() {
+
+			BeforeEach(func() {
+				responses["curl /v3/organizations?names=other-org"] = []string{`{"resources": [{"guid": "other-org-guid", "name": "other-org"}]}`}
+				responses["curl /v3/spaces?names=prod&organization_guids=other-org-guid"] = []string{`{"resources": [{"guid": "prod-guid", "name": "prod"}]}`}
+				responses["curl /v3/spaces?names=prod&organization_guids=org-guid"] = []string{`{"resources": [{"guid": "prod-guid", "name": "prod"}]}`}
+				responses["curl /v3/service_instances?names=pcc1&space_guids=prod-guid"] = []string{`{"resources": [{"guid": "instance-guid", "name": "pcc1"}]}`}
+				cliConnection.GetCurrentOrgReturns(plugin_models.Organization{OrganizationFields: plugin_models.OrganizationFields{Guid: "org-guid", Name: "org"}}, nil)
+			})
+
+			It("Finds the service instance given as <org>/<space>/<instance>", func() {
+				commandData.Target = "other-org/prod/pcc1"
+				err := pluginConnection.GetConnectionData(&commandData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(commandData.ConnnectionData.Username).To(Equal("cluster_operator_key1"))
+				Expect(cliConnection.GetCurrentSpaceCallCount()).To(BeZero())
+			})
+
+			It("Finds the service instance in the org and space given as options", func() {
+				commandData.UserCommand.Parameters["--org"] = "other-org"
+				commandData.UserCommand.Parameters["--space"] = "prod"
+				err := pluginConnection.GetConnectionData(&commandData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(commandData.ConnnectionData.Username).To(Equal("cluster_operator_key1"))
+			})
+
+			It("Looks up a space given without an org in the targeted org", func() {
+				commandData.UserCommand.Parameters["--space"] = "prod"
+				err := pluginConnection.GetConnectionData(&commandData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(commandData.ConnnectionData.Username).To(Equal("cluster_operator_key1"))
+			})
+
+			It("Reports an org or space that does not exist", func() {
+				commandData.Target = "missing/prod/pcc1"
+				responses["curl /v3/organizations?names=missing"] = []string{`{"resources": []}`}
+				err := pluginConnection.GetConnectionData(&commandData)
+				Expect(err).To(MatchError("Org missing not found"))
+
+				commandData.Target = "other-org/missing/pcc1"
+				responses["curl /v3/spaces?names=missing&organization_guids=other-org-guid"] = []string{`{"resources": []}`}
+				err = pluginConnection.GetConnectionData(&commandData)
+				Expect(err).To(MatchError("Space missing not found"))
+			})
+
+			It("Rejects invalid combinations of the target and the options", func() {
+				commandData.Target = "prod/pcc1"
+				Expect(pluginConnection.GetConnectionData(&commandData)).To(MatchError("Invalid target prod/pcc1, use <org>/<space>/<instance>"))
+
+				commandData.Target = "other-org/prod/pcc1"
+				commandData.UserCommand.Parameters["--space"] = "prod"
+				Expect(pluginConnection.GetConnectionData(&commandData)).To(MatchError("Use either the <org>/<space>/<instance> target or --org and --space"))
+
+				commandData.Target = "pcc1"
+				commandData.UserCommand.Parameters = map[string]string{"--org": "other-org"}
+				Expect(pluginConnection.GetConnectionData(&commandData)).To(MatchError("--org requires --space"))
+			})
+
+			It("Requires a targeted org for a space given without an org", func() {
+				cliConnection.GetCurrentOrgReturns(plugin_models.Organization{}, nil)
+				commandData.UserCommand.Parameters["--space"] = "prod"
+				err := pluginConnection.GetConnectionData(&commandData)
+				Expect(err).To(MatchError(ContainSubstring("No org targeted")))
+			})
+
+			It("Requires the v3 API", func() {
+				commandData.Target = "other-org/prod/pcc1"
+				responses["curl /v3/organizations?names=other-org"] = []string{`{"code": 10000, "description": "Unknown request"}`}
+				err := pluginConnection.GetConnectionData(&commandData)
+				Expect(err).To(MatchError(ContainSubstring("require the v3 API of Cloud Foundry")))
+			})
 		})
 	})
 
 	Context("We don't have a service-key", func() {
 
 		It("Returns an error indicating that there is no service-key", func() {
-			cliConnection.CliCommandWithoutTerminalOutputReturnsOnCall(0, []string{"", ""}, nil)
-			cliConnection.CliCommandWithoutTerminalOutputReturnsOnCall(1, []string{"", ""}, nil)
+			cliConnection.GetCurrentSpaceReturns(plugin_models.Space{SpaceFields: plugin_models.SpaceFields{Guid: "space-guid", Name: "dev"}}, nil)
+			respond(map[string][]string{instanceRequest: unknownRequest, "service-keys pcc1": {"", ""}})
 			err := pluginConnection.GetConnectionData(&commandData)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal(fmt.Sprintf(format.NoServiceKeyMessage, commandData.Target, commandData.Target)))
-			Expect(cliConnection.CliCommandWithoutTerminalOutputCallCount()).To(Equal(1))
+			Expect(cliConnection.CliCommandWithoutTerminalOutputCallCount()).To(Equal(2))
 			Expect(len(commandData.ConnnectionData.Username)).To(BeZero())
 			Expect(len(commandData.ConnnectionData.Password)).To(BeZero())
 			Expect(len(commandData.ConnnectionData.LocatorAddress)).To(BeZero())
@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package gemfire_test
+
+import (
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/cli/plugin/models"
+	"code.cloudfoundry.org/cli/plugin/pluginfakes"
+	. "github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/gemfire"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/implfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BasicPlugin", func() {
+
+	var (
+		cliConnection *pluginfakes.FakeCliConnection
+		comm          *implfakes.FakeCommandProcessor
+		commands      []string
+		serviceKey    string
+	)
+
+	BeforeEach(func() {
+		cliConnection = new(pluginfakes.FakeCliConnection)
+		comm = new(implfakes.FakeCommandProcessor)
+		commands = nil
+		cliConnection.GetCurrentSpaceReturns(plugin_models.Space{SpaceFields: plugin_models.SpaceFields{Guid: "space-guid", Name: "dev"}}, nil)
+		serviceKey = `{"urls": {"gfsh": "https://cloudcache-1.sys.example.com/gemfire/v1"}, "users": [{"password": "secret", "roles": ["cluster_operator"], "username": "cluster_operator_1"}]}`
+		cliConnection.CliCommandWithoutTerminalOutputStub = func(args ...string) ([]string, error) {
+			commands = append(commands, strings.Join(args, " "))
+			switch args[0] {
+			case "curl":
+				return []string{`{"code": 10000, "description": "Unknown request"}`}, nil
+			case "create-service-key", "delete-service-key":
+				return []string{"OK"}, nil
+			case "service-keys":
+				return []string{"name", "other-key", commands[0][len("create-service-key pcc1 "):]}, nil
+			case "service-key":
+				return []string{"Getting key", "", serviceKey}, nil
+			}
+			return nil, errors.New("unexpected command")
+		}
+	})
+
+	Context("--ephemeral-key", func() {
+
+		It("Creates a service key for the command and deletes it afterwards", func() {
+			basicPlugin, err := NewBasicPlugin(comm)
+			Expect(err).NotTo(HaveOccurred())
+			basicPlugin.Run(cliConnection, []string{"gemfire", "pcc1", "list", "regions", "--ephemeral-key"})
+
+			Expect(commands[0]).To(HavePrefix("create-service-key pcc1 gemfire-ephemeral-"))
+			keyName := strings.TrimPrefix(commands[0], "create-service-key pcc1 ")
+			Expect(commands).To(ContainElement("service-key pcc1 " + keyName))
+			Expect(commands[len(commands)-1]).To(Equal("delete-service-key -f pcc1 " + keyName))
+
+			Expect(comm.ProcessCommandCallCount()).To(Equal(1))
+			commandData := comm.ProcessCommandArgsForCall(0)
+			Expect(commandData.ConnnectionData.Username).To(Equal("cluster_operator_1"))
+		})
+
+		It("Uses the existing service keys without the option", func() {
+			basicPlugin, err := NewBasicPlugin(comm)
+			Expect(err).NotTo(HaveOccurred())
+			cliConnection.CliCommandWithoutTerminalOutputStub = func(args ...string) ([]string, error) {
+				commands = append(commands, strings.Join(args, " "))
+				switch args[0] {
+				case "curl":
+					return []string{`{"code": 10000, "description": "Unknown request"}`}, nil
+				case "service-keys":
+					return []string{"name", "other-key"}, nil
+				case "service-key":
+					return []string{"Getting key", "", serviceKey}, nil
+				}
+				return nil, errors.New("unexpected command")
+			}
+			basicPlugin.Run(cliConnection, []string{"gemfire", "pcc1", "list", "regions"})
+			Expect(commands).To(ContainElement("service-key pcc1 other-key"))
+			Expect(strings.Join(commands, "\n")).NotTo(ContainSubstring("create-service-key"))
+		})
+	})
+})
@@ -0,0 +1,227 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package gemfire
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"code.cloudfoundry.org/cli/cf/errors"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+)
+
+// errNoCredentialBindings reports a CF API that does not provide service credential bindings, or a
+// cf CLI without 'cf curl', in which case the service key is read from the output of the cf CLI
+var errNoCredentialBindings = errors.New("service credential bindings are not available")
+
+// unknownRequestCode is the error code of the CF API for endpoints it does not provide
+const unknownRequestCode = 10000
+
+type v3Error struct {
+	Code   int    `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+type v3Resources struct {
+	Resources []struct {
+		GUID string `json:"guid"`
+		Name string `json:"name"`
+	} `json:"resources"`
+}
+
+type v3CredentialBindingDetails struct {
+	Credentials *domain.ServiceKey `json:"credentials"`
+}
+
+type v3Errors struct {
+	Errors []v3Error `json:"errors"`
+	// Code is reported by older APIs which do not know the v3 endpoints
+	Code int `json:"code"`
+}
+
+// instanceLocation names a service instance and, when it is not in the targeted space, its org
+// and space
+type instanceLocation struct {
+	org   string
+	space string
+	name  string
+}
+
+// parseInstanceLocation reads the location of the service instance from an '<org>/<space>/<instance>'
+// target or from the '--org' and '--space' options. Without an org the space is in the targeted org
+func parseInstanceLocation(commandData *domain.CommandData) (instanceLocation, error) {
+	parameters := commandData.UserCommand.Parameters
+	location := instanceLocation{
+		org:   common.GetOption(parameters, []string{"--org"}),
+		space: common.GetOption(parameters, []string{"--space"}),
+		name:  commandData.Target,
+	}
+	if strings.Contains(commandData.Target, "/") {
+		parts := strings.Split(commandData.Target, "/")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return location, errors.New("Invalid target " + commandData.Target + ", use <org>/<space>/<instance>")
+		}
+		if location.org != "" || location.space != "" {
+			return location, errors.New("Use either the <org>/<space>/<instance> target or --org and --space")
+		}
+		location = instanceLocation{org: parts[0], space: parts[1], name: parts[2]}
+	}
+	if location.org != "" && location.space == "" {
+		return location, errors.New("--org requires --space")
+	}
+	return location, nil
+}
+
+// inTargetedSpace reports whether the service instance is in the space targeted by the cf CLI
+func (l instanceLocation) inTargetedSpace() bool {
+	return l.space == ""
+}
+
+// spaceGUID provides the GUID of the space of the service instance, looking up other spaces by
+// name through the CF v3 API
+func (pc *pluginConnection) spaceGUID(location instanceLocation) (string, error) {
+	if location.inTargetedSpace() {
+		space, err := pc.cliConnection.GetCurrentSpace()
+		if err != nil {
+			return "", err
+		}
+		if space.Guid == "" {
+			return "", errors.New("No space targeted, use 'cf target -s <space>' or --org and --space")
+		}
+		return space.Guid, nil
+	}
+	var orgGUID string
+	if location.org == "" {
+		org, err := pc.cliConnection.GetCurrentOrg()
+		if err != nil {
+			return "", err
+		}
+		if org.Guid == "" {
+			return "", errors.New("No org targeted, use 'cf target -o <org>' or --org")
+		}
+		orgGUID = org.Guid
+	} else {
+		var orgs v3Resources
+		err := pc.curl("/v3/organizations?names="+url.QueryEscape(location.org), &orgs)
+		if err != nil {
+			return "", err
+		}
+		if orgs.Resources == nil {
+			return "", errNoCredentialBindings
+		}
+		if len(orgs.Resources) == 0 {
+			return "", errors.New("Org " + location.org + " not found")
+		}
+		orgGUID = orgs.Resources[0].GUID
+	}
+
+	var spaces v3Resources
+	err := pc.curl("/v3/spaces?names="+url.QueryEscape(location.space)+"&organization_guids="+url.QueryEscape(orgGUID), &spaces)
+	if err != nil {
+		return "", err
+	}
+	if spaces.Resources == nil {
+		return "", errNoCredentialBindings
+	}
+	if len(spaces.Resources) == 0 {
+		return "", errors.New("Space " + location.space + " not found")
+	}
+	return spaces.Resources[0].GUID, nil
+}
+
+// getServiceKeyFromAPI reads the service key through the CF v3 API: the GUID of the service
+// instance in its space, then its credential bindings of type key, then their details
+func (pc *pluginConnection) getServiceKeyFromAPI(commandData *domain.CommandData, location instanceLocation) (domain.ServiceKey, error) {
+	spaceGUID, err := pc.spaceGUID(location)
+	if err != nil {
+		return domain.ServiceKey{}, err
+	}
+
+	var instances v3Resources
+	err = pc.curl("/v3/service_instances?names="+url.QueryEscape(location.name)+"&space_guids="+url.QueryEscape(spaceGUID), &instances)
+	if err != nil {
+		return domain.ServiceKey{}, err
+	}
+	if instances.Resources == nil {
+		return domain.ServiceKey{}, errNoCredentialBindings
+	}
+	if len(instances.Resources) == 0 {
+		return domain.ServiceKey{}, errors.New("Service instance " + commandData.Target + " not found")
+	}
+
+	names, guids, err := pc.listServiceKeyBindings(instances.Resources[0].GUID)
+	if err != nil {
+		return domain.ServiceKey{}, err
+	}
+	name, err := selectServiceKey(commandData, names)
+	if err != nil {
+		return domain.ServiceKey{}, err
+	}
+
+	var details v3CredentialBindingDetails
+	err = pc.curl("/v3/service_credential_bindings/"+guids[name]+"/details", &details)
+	if err != nil {
+		return domain.ServiceKey{}, err
+	}
+	if details.Credentials == nil {
+		return domain.ServiceKey{}, errNoCredentialBindings
+	}
+	return *details.Credentials, nil
+}
+
+// listServiceKeyBindings lists the names of the service keys of a service instance and their GUIDs
+// by name, without reading their credentials
+func (pc *pluginConnection) listServiceKeyBindings(instanceGUID string) ([]string, map[string]string, error) {
+	var bindings v3Resources
+	err := pc.curl("/v3/service_credential_bindings?type=key&per_page=5000&service_instance_guids="+url.QueryEscape(instanceGUID), &bindings)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bindings.Resources == nil {
+		return nil, nil, errNoCredentialBindings
+	}
+	var names []string
+	guids := make(map[string]string)
+	for _, binding := range bindings.Resources {
+		names = append(names, binding.Name)
+		guids[binding.Name] = binding.GUID
+	}
+	return names, guids, nil
+}
+
+// curl requests a path of the CF API with 'cf curl' and decodes the response. A response that is
+// not v3 JSON, or that reports an unknown request, is errNoCredentialBindings
+func (pc *pluginConnection) curl(path string, response interface{}) error {
+	output, err := pc.cliConnection.CliCommandWithoutTerminalOutput("curl", path)
+	if err != nil {
+		return err
+	}
+	content := []byte(strings.Join(output, "\n"))
+	var apiErrors v3Errors
+	if json.Unmarshal(content, &apiErrors) != nil || apiErrors.Code == unknownRequestCode {
+		return errNoCredentialBindings
+	}
+	for _, apiError := range apiErrors.Errors {
+		if apiError.Code == unknownRequestCode {
+			return errNoCredentialBindings
+		}
+		return errors.New("The CF API request " + path + " failed. " + apiError.Title + ": " + apiError.Detail)
+	}
+	return json.Unmarshal(content, response)
+}
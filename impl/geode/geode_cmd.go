@@ -21,28 +21,53 @@ import (
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/cache"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/clusterconfig"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/completion"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/config"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/format"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/shell"
+	"os"
+	"strings"
 )
 
 // Command is the basic struct that the command works on
 type command struct {
-	commandData domain.CommandData
-	comm        impl.CommandProcessor
+	commandData    domain.CommandData
+	comm           impl.CommandProcessor
+	processRequest impl.RequestHelper
 }
 
-// New provides a constructor for the Geode standalone implementation for the client
-func New(comm impl.CommandProcessor) (command, error) {
+// New provides a constructor for the Geode standalone implementation for the client. The
+// requester is used to contact the token endpoint when logging in
+func New(comm impl.CommandProcessor, processRequest impl.RequestHelper) (command, error) {
 	if comm == nil {
 		return command{}, errors.New("command processor is not valid")
 	}
-	return command{comm: comm}, nil
+	if processRequest == nil {
+		return command{}, errors.New("requester is not valid")
+	}
+	return command{comm: comm, processRequest: processRequest}, nil
 }
 
 // Run is the main entry point for the standalone Geode command line interface
 // It is run once for each command executed
 func (gc *command) Run(args []string) (err error) {
+	if len(args) > 1 && args[1] == completion.CompleteCommand {
+		return gc.printCompletions(args[2:])
+	}
 
-	gc.commandData.Target, gc.commandData.UserCommand = common.GetTargetAndClusterCommand(args)
+	configuration, err := loadConfig()
+	if err != nil {
+		return
+	}
+	if len(args) > 1 && args[1] == "config" {
+		return runConfig(configuration, args[2:])
+	}
+	if len(args) > 1 && common.Contains(clusterconfig.TwoClusterCommands, args[1]) {
+		return clusterconfig.RunTwoClusterCommand(gc.comm, gc.connectTo(configuration), common.ParseUserCommand(args[1:]))
+	}
+	gc.parseArgs(configuration, args)
 
 	if common.HasOption(gc.commandData.UserCommand.Parameters, []string{"-v", "--version"}) {
 		fmt.Printf("Version: %d.%d.%d\n", domain.VersionType.Major, domain.VersionType.Minor, domain.VersionType.Build)
@@ -55,6 +80,10 @@ func (gc *command) Run(args []string) (err error) {
 		return
 	}
 
+	if strings.HasPrefix(gc.commandData.UserCommand.Command, "completion") {
+		return gc.printCompletionScript()
+	}
+
 	geodeConnection := &GeodeConnection{}
 
 	err = geodeConnection.GetConnectionData(&gc.commandData)
@@ -63,12 +92,167 @@ func (gc *command) Run(args []string) (err error) {
 		return
 	}
 
+	if gc.commandData.UserCommand.Command != "login" {
+		err = gc.applyLoginToken(&gc.commandData)
+		if err != nil {
+			return
+		}
+	}
+	err = geodeConnection.PromptPassword(&gc.commandData.ConnnectionData)
+	if err != nil {
+		return
+	}
+	if gc.commandData.UserCommand.Command == "login" {
+		return gc.login()
+	}
+
+	if gc.commandData.UserCommand.Command == "shell" {
+		interactive, err := shell.New(gc.comm, os.Stdin, os.Stdout)
+		if err != nil {
+			return err
+		}
+		return interactive.Run(&gc.commandData)
+	}
+
 	// From this point common code can handle the processing of the command
 	err = gc.comm.ProcessCommand(&gc.commandData)
 
 	return
 }
 
+// printCompletionScript writes the completion script for a shell, including the commands of the
+// target when one is given
+func (gc *command) printCompletionScript() error {
+	words := strings.Fields(gc.commandData.UserCommand.Command)
+	if len(words) != 2 {
+		return errors.New("usage: gemfire [<target>] completion <" + strings.Join(completion.Shells, "|") + ">")
+	}
+
+	if gc.commandData.Target != "" {
+		err := gc.connect()
+		if err != nil {
+			return err
+		}
+		err = gc.comm.LoadEndPoints(&gc.commandData)
+		if err != nil {
+			return err
+		}
+	}
+
+	script, err := completion.Script(words[1], "gemfire", gc.commandData.AvailableEndpoints)
+	if err != nil {
+		return err
+	}
+	fmt.Print(script)
+	return nil
+}
+
+// printCompletions writes the candidates for the last of the words typed after the program name,
+// one per line. It backs the completion scripts, so failures result in no candidates
+func (gc *command) printCompletions(words []string) error {
+	if len(words) == 0 {
+		return nil
+	}
+	configuration, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	gc.parseArgs(configuration, append([]string{"gemfire"}, words[:len(words)-1]...))
+	if gc.commandData.Target == "" {
+		return nil
+	}
+	if words[0] == gc.commandData.Target {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	err = gc.connect()
+	if err != nil {
+		return nil
+	}
+	err = gc.comm.LoadEndPoints(&gc.commandData)
+	if err != nil {
+		return nil
+	}
+	valuesCache, err := cache.ValuesFromEnvironment()
+	if err != nil {
+		return nil
+	}
+	completer, err := completion.NewCompleter(gc.comm, valuesCache)
+	if err != nil {
+		return nil
+	}
+	for _, candidate := range completer.Complete(&gc.commandData, words) {
+		fmt.Println(candidate)
+	}
+	return nil
+}
+
+// parseArgs extracts the target and command from the args. Without a target in the args or the
+// environment, the target of the current profile is used, and the settings of the profile apply
+// to commands against that target
+func (gc *command) parseArgs(configuration *config.Config, args []string) {
+	profile, _ := configuration.Current()
+	defaultTarget := os.Getenv("GEODE_TARGET")
+	namesTarget := len(args) > 1 && strings.Contains(args[1], "://")
+	if defaultTarget == "" && !namesTarget {
+		defaultTarget = profile.Target
+	}
+	gc.commandData.Target, gc.commandData.UserCommand = common.SplitTargetAndCommand(args, defaultTarget)
+	if profile.Target != "" && gc.commandData.Target == profile.Target {
+		profile.Apply(&gc.commandData)
+	}
+}
+
+func loadConfig() (*config.Config, error) {
+	path, err := config.Path()
+	if err != nil {
+		return nil, err
+	}
+	return config.Load(path)
+}
+
+// runConfig lists the profiles, selects the current one or changes their settings
+func runConfig(configuration *config.Config, words []string) (err error) {
+	usage := errors.New("usage: gemfire config list | gemfire config use <profile> | " +
+		"gemfire config set <profile> <" + strings.Join(config.Keys, "|") + "> [<value>]")
+	if len(words) == 0 {
+		return usage
+	}
+	switch {
+	case words[0] == "list" && len(words) == 1:
+		if len(configuration.Profiles) == 0 {
+			fmt.Println("No profiles, create one with 'gemfire config set <profile> target <url>'")
+			return
+		}
+		fmt.Println(configuration.List())
+		return
+	case words[0] == "use" && len(words) == 2:
+		err = configuration.Use(words[1])
+		if err != nil {
+			return
+		}
+		err = configuration.Save()
+		if err == nil {
+			fmt.Println("Switched to profile " + words[1])
+		}
+		return
+	case words[0] == "set" && (len(words) == 3 || len(words) == 4):
+		var value string
+		if len(words) == 4 {
+			value = words[3]
+		}
+		err = configuration.Set(words[1], words[2], value)
+		if err != nil {
+			return
+		}
+		return configuration.Save()
+	}
+	return usage
+}
+
 func printHelp() {
 	fmt.Println("Commands to interact with a Geode cluster.")
 	fmt.Println("")
@@ -77,7 +261,17 @@ func printHelp() {
 	fmt.Println("\ttarget: \n\t\tURL to a Geode locator in the form of: http(s)://host:port")
 	fmt.Println("\t\tOptional if 'GEODE_TARGET' environment variable is set")
 	fmt.Println("\tcommand:\n\t\t'gemfire <target> commands' lists available commands")
+	fmt.Println("\t\t'gemfire <target> shell' starts an interactive session against the target")
+	fmt.Println("\t\t'gemfire [<target>] completion <bash|zsh|fish>' prints a shell completion script")
+	fmt.Println("\t\t'gemfire <target> login --token-url <url> --client-id <id>' obtains a token from an OAuth2 provider for the commands that follow")
+	fmt.Println("\t\t'gemfire <target> plan -f <manifest>' compares the cluster with a manifest, 'apply -f <manifest> [--yes]' makes the changes")
+	fmt.Println("\t\t'gemfire <target> export-config [--dir <directory>]' exports the configuration of the cluster as manifests")
+	fmt.Println("\t\t'gemfire diff <target> <target>' compares the configuration of two clusters, each named by a URL or a profile")
+	fmt.Println("\t\t'gemfire copy <region|index|disk-store|gateway-receiver> --id <id> --from <target> --to <target>' creates an entity of one cluster on another, --all copies every entity")
+	fmt.Println("\t\t'gemfire config <list|use|set>' manages named connection profiles, the target of the current profile is used when none is given")
 	fmt.Println("\toptions:\n\t\t'gemfire <target> <command> -h' lists options for an individual command")
 	fmt.Println(format.GeneralOptions)
+	fmt.Println("\t\t--password-file <file_path> or --password-stdin reads the password, which is prompted for when a username is given without one")
+	fmt.Println("\t\t--credential-helper <command>, or a 'GEODE_CREDENTIAL_HELPER' environment variable runs a command printing {\"username\",\"password\"} or {\"token\",\"expiresAt\"} JSON instead of reading 'GEODE_PASSWORD'")
 	fmt.Println("\thelp:\n\t\t--help, -h for general help, and provide <target> and <command> for command-specific help")
 }
@@ -18,6 +18,7 @@ package geode
 import (
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/credential"
 	"os"
 	"strings"
 )
@@ -37,8 +38,45 @@ func (gc *GeodeConnection) GetConnectionData(commandData *domain.CommandData) er
 	}
 	commandData.ConnnectionData.Password = common.GetOption(commandData.UserCommand.Parameters, []string{"--password", "-p"})
 	if commandData.ConnnectionData.Password == "" {
+		password, _, err := common.ReadPassword(commandData.UserCommand.Parameters, os.Stdin)
+		if err != nil {
+			return err
+		}
+		commandData.ConnnectionData.Password = password
+	}
+	// a credential helper replaces the password environment variable, but not credentials given as options
+	credentialHelper := common.GetOption(commandData.UserCommand.Parameters, []string{"--credential-helper"})
+	if credentialHelper == "" {
+		credentialHelper = os.Getenv("GEODE_CREDENTIAL_HELPER")
+	}
+	useHelper := credentialHelper != "" && commandData.ConnnectionData.Password == "" &&
+		!common.HasOption(commandData.UserCommand.Parameters, []string{"--token", "--token-file"})
+	if useHelper {
+		err := credential.Apply(credentialHelper, &commandData.ConnnectionData)
+		if err != nil {
+			return err
+		}
+	} else if commandData.ConnnectionData.Password == "" {
 		commandData.ConnnectionData.Password = os.Getenv("GEODE_PASSWORD")
 	}
+	common.ApplyTLSOptions(commandData)
 
+	err := common.ApplyAuthOptions(commandData)
+	if err != nil {
+		return err
+	}
+	if useHelper && commandData.ConnnectionData.Token != "" && commandData.ConnnectionData.AuthMode == "" {
+		commandData.ConnnectionData.AuthMode = common.AuthToken
+	}
 	return nil
 }
+
+// PromptPassword asks for the password when a username is given without one and no token is
+// known, rather than have the password in the command line or the environment
+func (gc *GeodeConnection) PromptPassword(connectionData *domain.ConnectionData) (err error) {
+	if connectionData.Username != "" && connectionData.Password == "" && connectionData.Token == "" &&
+		connectionData.AuthMode != common.AuthToken {
+		connectionData.Password, err = common.PromptPassword(connectionData.Username)
+	}
+	return
+}
@@ -16,6 +16,9 @@
 package geode_test
 
 import (
+	"io/ioutil"
+	"os"
+
 	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -52,6 +55,95 @@ var _ = Describe("GeodeConnection", func() {
 			Expect(commandData.ConnnectionData.Username).To(Equal("locatorUser"))
 			Expect(commandData.ConnnectionData.Password).To(Equal("locatorPassword"))
 			Expect(commandData.ConnnectionData.LocatorAddress).To(Equal("https://some.geode-locator.com"))
+			Expect(commandData.ConnnectionData.SkipSSLValidation).To(BeFalse())
+		})
+
+		It("Applies the TLS options", func() {
+			commandData.UserCommand.Parameters["--ca-cert"] = "ca.pem"
+			commandData.UserCommand.Parameters["--skip-ssl-validation"] = ""
+			err := geodeConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.CACert).To(Equal("ca.pem"))
+			Expect(commandData.ConnnectionData.SkipSSLValidation).To(BeTrue())
+		})
+
+		It("Applies the token options", func() {
+			commandData.UserCommand.Parameters["--token"] = "abc"
+			commandData.UserCommand.Parameters["--auth"] = "token"
+			err := geodeConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Token).To(Equal("abc"))
+			Expect(commandData.ConnnectionData.AuthMode).To(Equal("token"))
+		})
+
+		It("Prefers the password option to the credential helper", func() {
+			commandData.UserCommand.Parameters["--credential-helper"] = "exit 1"
+			err := geodeConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Password).To(Equal("locatorPassword"))
+		})
+
+		It("Reads the password file when no password is given", func() {
+			passwordFile, err := ioutil.TempFile("", "password")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(passwordFile.Name())
+			_, err = passwordFile.WriteString("filePassword\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passwordFile.Close()).To(Succeed())
+
+			delete(commandData.UserCommand.Parameters, "-p")
+			commandData.UserCommand.Parameters["--password-file"] = passwordFile.Name()
+			err = geodeConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Password).To(Equal("filePassword"))
+		})
+
+		It("Does not prompt for the password unless stdin is a terminal", func() {
+			connectionData := domain.ConnectionData{Username: "locatorUser"}
+			err := (&GeodeConnection{}).PromptPassword(&connectionData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(connectionData.Password).To(BeEmpty())
+		})
+
+		It("Reports invalid token options", func() {
+			commandData.UserCommand.Parameters["--token-file"] = "missing-token"
+			err := geodeConnection.GetConnectionData(&commandData)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("A credential helper is configured", func() {
+
+		BeforeEach(func() {
+			commandData.Target = "https://some.geode-locator.com"
+			os.Setenv("GEODE_PASSWORD", "environmentPassword")
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("GEODE_PASSWORD")
+		})
+
+		It("Uses the password of the helper instead of the environment", func() {
+			commandData.UserCommand.Parameters["--credential-helper"] = `echo '{"username":"helperUser","password":"helperPassword"}'`
+			err := geodeConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Username).To(Equal("helperUser"))
+			Expect(commandData.ConnnectionData.Password).To(Equal("helperPassword"))
+		})
+
+		It("Authenticates with the token of the helper", func() {
+			commandData.UserCommand.Parameters["--credential-helper"] = `echo '{"token":"helperToken"}'`
+			err := geodeConnection.GetConnectionData(&commandData)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commandData.ConnnectionData.Token).To(Equal("helperToken"))
+			Expect(commandData.ConnnectionData.AuthMode).To(Equal("token"))
+			Expect(commandData.ConnnectionData.TokenRenewer).NotTo(BeNil())
+		})
+
+		It("Reports a failing helper", func() {
+			commandData.UserCommand.Parameters["--credential-helper"] = "exit 1"
+			err := geodeConnection.GetConnectionData(&commandData)
+			Expect(err).To(HaveOccurred())
 		})
 	})
 })
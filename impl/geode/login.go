@@ -0,0 +1,132 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more contributor license
+ * agreements. See the NOTICE file distributed with this work for additional information regarding
+ * copyright ownership. The ASF licenses this file to You under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance with the License. You may obtain a
+ * copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ */
+
+package geode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/domain"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/clusterconfig"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/config"
+	"github.com/gemfire/tanzu-gemfire-management-cf-plugin/impl/common/oauth"
+)
+
+// login obtains a token from the token endpoint of the OAuth2 provider of the target and stores it
+// for the commands that follow. The password grant is used when a username and password are given
+func (gc *command) login() error {
+	parameters := gc.commandData.UserCommand.Parameters
+	connectionData := gc.commandData.ConnnectionData
+	grant := oauth.Grant{
+		Type:         common.GetOption(parameters, []string{"--grant"}),
+		TokenURL:     common.GetOptionOrEnv(parameters, "--token-url", "GEODE_TOKEN_URL"),
+		ClientID:     common.GetOptionOrEnv(parameters, "--client-id", "GEODE_CLIENT_ID"),
+		ClientSecret: clientSecret(parameters),
+		Scope:        common.GetOption(parameters, []string{"--scope"}),
+		Username:     connectionData.Username,
+		Password:     connectionData.Password,
+	}
+	if grant.Type == "" {
+		grant.Type = oauth.GrantClientCredentials
+		if grant.Username != "" && grant.Password != "" {
+			grant.Type = oauth.GrantPassword
+		}
+	}
+	if grant.TokenURL == "" || grant.ClientID == "" {
+		return errors.New("usage: gemfire <target> login --token-url <url> --client-id <id> [--client-secret <secret>] " +
+			"[--grant " + oauth.GrantClientCredentials + "|" + oauth.GrantPassword + "] [--scope <scopes>] [-u <username> -p <password>]")
+	}
+
+	client, err := oauth.NewClient(gc.processRequest)
+	if err != nil {
+		return err
+	}
+	token, err := client.Login(connectionData, grant)
+	if err != nil {
+		return err
+	}
+	tokenStore, err := oauth.TokenStoreFromEnvironment()
+	if err != nil {
+		return err
+	}
+	err = tokenStore.Store(connectionData.LocatorAddress, token)
+	if err != nil {
+		return err
+	}
+
+	message := "Logged in to " + connectionData.LocatorAddress
+	if !token.Expiry.IsZero() {
+		message += ", the token expires at " + token.Expiry.Local().Format("2006-01-02 15:04:05")
+	}
+	fmt.Println(message)
+	return nil
+}
+
+// connect provides the connection data of the target, including the token obtained by logging in
+func (gc *command) connect() error {
+	geodeConnection := &GeodeConnection{}
+	err := geodeConnection.GetConnectionData(&gc.commandData)
+	if err != nil {
+		return err
+	}
+	return gc.applyLoginToken(&gc.commandData)
+}
+
+// applyLoginToken provides the token obtained by logging in to the target, if any
+func (gc *command) applyLoginToken(commandData *domain.CommandData) error {
+	tokenStore, err := oauth.TokenStoreFromEnvironment()
+	if err != nil {
+		return err
+	}
+	client, err := oauth.NewClient(gc.processRequest)
+	if err != nil {
+		return err
+	}
+	parameters := commandData.UserCommand.Parameters
+	return client.ApplyLoginToken(&commandData.ConnnectionData, tokenStore, clientSecret(parameters))
+}
+
+// connectTo provides the connection data of a target named by a locator URL or a profile, whose
+// settings then apply to the target
+func (gc *command) connectTo(configuration *config.Config) clusterconfig.Connector {
+	return func(commandData *domain.CommandData) error {
+		if profile, found := configuration.Profiles[commandData.Target]; found {
+			if profile.Target == "" {
+				return errors.New("The profile " + commandData.Target + " has no target")
+			}
+			commandData.Target = profile.Target
+			profile.Apply(commandData)
+		} else if !strings.Contains(commandData.Target, "://") {
+			return errors.New("Unknown target " + commandData.Target + ", use a locator URL or a profile")
+		}
+		geodeConnection := &GeodeConnection{}
+		err := geodeConnection.GetConnectionData(commandData)
+		if err != nil {
+			return err
+		}
+		err = gc.applyLoginToken(commandData)
+		if err != nil {
+			return err
+		}
+		return geodeConnection.PromptPassword(&commandData.ConnnectionData)
+	}
+}
+
+func clientSecret(parameters map[string]string) string {
+	return common.GetOptionOrEnv(parameters, "--client-secret", "GEODE_CLIENT_SECRET")
+}
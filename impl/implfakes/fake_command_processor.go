@@ -9,6 +9,30 @@ import (
 )
 
 type FakeCommandProcessor struct {
+	ExecuteCommandStub        func(*domain.CommandData) (string, error)
+	executeCommandMutex       sync.RWMutex
+	executeCommandArgsForCall []struct {
+		arg1 *domain.CommandData
+	}
+	executeCommandReturns struct {
+		result1 string
+		result2 error
+	}
+	executeCommandReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	LoadEndPointsStub        func(*domain.CommandData) error
+	loadEndPointsMutex       sync.RWMutex
+	loadEndPointsArgsForCall []struct {
+		arg1 *domain.CommandData
+	}
+	loadEndPointsReturns struct {
+		result1 error
+	}
+	loadEndPointsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ProcessCommandStub        func(*domain.CommandData) error
 	processCommandMutex       sync.RWMutex
 	processCommandArgsForCall []struct {
@@ -24,6 +48,129 @@ type FakeCommandProcessor struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeCommandProcessor) ExecuteCommand(arg1 *domain.CommandData) (string, error) {
+	fake.executeCommandMutex.Lock()
+	ret, specificReturn := fake.executeCommandReturnsOnCall[len(fake.executeCommandArgsForCall)]
+	fake.executeCommandArgsForCall = append(fake.executeCommandArgsForCall, struct {
+		arg1 *domain.CommandData
+	}{arg1})
+	fake.recordInvocation("ExecuteCommand", []interface{}{arg1})
+	fake.executeCommandMutex.Unlock()
+	if fake.ExecuteCommandStub != nil {
+		return fake.ExecuteCommandStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.executeCommandReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCommandProcessor) ExecuteCommandCallCount() int {
+	fake.executeCommandMutex.RLock()
+	defer fake.executeCommandMutex.RUnlock()
+	return len(fake.executeCommandArgsForCall)
+}
+
+func (fake *FakeCommandProcessor) ExecuteCommandCalls(stub func(*domain.CommandData) (string, error)) {
+	fake.executeCommandMutex.Lock()
+	defer fake.executeCommandMutex.Unlock()
+	fake.ExecuteCommandStub = stub
+}
+
+func (fake *FakeCommandProcessor) ExecuteCommandArgsForCall(i int) *domain.CommandData {
+	fake.executeCommandMutex.RLock()
+	defer fake.executeCommandMutex.RUnlock()
+	argsForCall := fake.executeCommandArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCommandProcessor) ExecuteCommandReturns(result1 string, result2 error) {
+	fake.executeCommandMutex.Lock()
+	defer fake.executeCommandMutex.Unlock()
+	fake.ExecuteCommandStub = nil
+	fake.executeCommandReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCommandProcessor) ExecuteCommandReturnsOnCall(i int, result1 string, result2 error) {
+	fake.executeCommandMutex.Lock()
+	defer fake.executeCommandMutex.Unlock()
+	fake.ExecuteCommandStub = nil
+	if fake.executeCommandReturnsOnCall == nil {
+		fake.executeCommandReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.executeCommandReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCommandProcessor) LoadEndPoints(arg1 *domain.CommandData) error {
+	fake.loadEndPointsMutex.Lock()
+	ret, specificReturn := fake.loadEndPointsReturnsOnCall[len(fake.loadEndPointsArgsForCall)]
+	fake.loadEndPointsArgsForCall = append(fake.loadEndPointsArgsForCall, struct {
+		arg1 *domain.CommandData
+	}{arg1})
+	fake.recordInvocation("LoadEndPoints", []interface{}{arg1})
+	fake.loadEndPointsMutex.Unlock()
+	if fake.LoadEndPointsStub != nil {
+		return fake.LoadEndPointsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.loadEndPointsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeCommandProcessor) LoadEndPointsCallCount() int {
+	fake.loadEndPointsMutex.RLock()
+	defer fake.loadEndPointsMutex.RUnlock()
+	return len(fake.loadEndPointsArgsForCall)
+}
+
+func (fake *FakeCommandProcessor) LoadEndPointsCalls(stub func(*domain.CommandData) error) {
+	fake.loadEndPointsMutex.Lock()
+	defer fake.loadEndPointsMutex.Unlock()
+	fake.LoadEndPointsStub = stub
+}
+
+func (fake *FakeCommandProcessor) LoadEndPointsArgsForCall(i int) *domain.CommandData {
+	fake.loadEndPointsMutex.RLock()
+	defer fake.loadEndPointsMutex.RUnlock()
+	argsForCall := fake.loadEndPointsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCommandProcessor) LoadEndPointsReturns(result1 error) {
+	fake.loadEndPointsMutex.Lock()
+	defer fake.loadEndPointsMutex.Unlock()
+	fake.LoadEndPointsStub = nil
+	fake.loadEndPointsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCommandProcessor) LoadEndPointsReturnsOnCall(i int, result1 error) {
+	fake.loadEndPointsMutex.Lock()
+	defer fake.loadEndPointsMutex.Unlock()
+	fake.LoadEndPointsStub = nil
+	if fake.loadEndPointsReturnsOnCall == nil {
+		fake.loadEndPointsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.loadEndPointsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeCommandProcessor) ProcessCommand(arg1 *domain.CommandData) error {
 	fake.processCommandMutex.Lock()
 	ret, specificReturn := fake.processCommandReturnsOnCall[len(fake.processCommandArgsForCall)]
@@ -87,6 +234,10 @@ func (fake *FakeCommandProcessor) ProcessCommandReturnsOnCall(i int, result1 err
 func (fake *FakeCommandProcessor) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.executeCommandMutex.RLock()
+	defer fake.executeCommandMutex.RUnlock()
+	fake.loadEndPointsMutex.RLock()
+	defer fake.loadEndPointsMutex.RUnlock()
 	fake.processCommandMutex.RLock()
 	defer fake.processCommandMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}